@@ -0,0 +1,12 @@
+-- withdrawal lifecycle
+ALTER TABLE appuser
+    ADD COLUMN withdrawn_at  timestamptz NULL,
+    ADD COLUMN anonymized_at timestamptz NULL;
+
+UPDATE appuser
+SET withdrawn_at = modified_at
+WHERE withdraw = true;
+
+CREATE INDEX ix_appuser_withdrawn_at
+    ON appuser (withdrawn_at)
+    WHERE withdraw = true AND anonymized_at IS NULL;
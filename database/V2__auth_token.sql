@@ -0,0 +1,34 @@
+-- login credentials and refresh tokens
+CREATE TABLE appuser_credential
+(
+    appuser_id    bigint       NOT NULL PRIMARY KEY REFERENCES appuser (id) ON DELETE CASCADE,
+    created_at    timestamptz  NOT NULL DEFAULT now(),
+    modified_at   timestamptz  NOT NULL DEFAULT now(),
+--
+    login         varchar(128) NOT NULL UNIQUE,
+    password_hash text         NOT NULL
+);
+CREATE TRIGGER tr_appuser_credential_update_modified_at
+    BEFORE UPDATE
+    ON appuser_credential
+    FOR EACH ROW
+    EXECUTE PROCEDURE fn_set_modified_at();
+
+-- token_hash : refresh token 의 sha256 (원문은 저장하지 않음)
+-- family     : login 한 번에서 rotation 으로 이어지는 token 묶음. 재사용이 감지되면 family 전체를 revoke
+CREATE TABLE refresh_token
+(
+    id         bigserial   NOT NULL PRIMARY KEY,
+    appuser_id bigint      NOT NULL REFERENCES appuser (id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+--
+    family     uuid        NOT NULL,
+    token_hash varchar(64) NOT NULL UNIQUE,
+    expires_at timestamptz NOT NULL,
+    used_at    timestamptz NULL,
+    revoked_at timestamptz NULL
+);
+CREATE INDEX ix_refresh_token_family
+    ON refresh_token (family);
+CREATE INDEX ix_refresh_token_expires_at
+    ON refresh_token (expires_at);
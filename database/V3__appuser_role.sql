@@ -0,0 +1,3 @@
+-- roles : 토큰의 roles 클레임으로 발급됨 (예. admin)
+ALTER TABLE appuser
+    ADD COLUMN role varchar(32)[] NOT NULL DEFAULT '{}';
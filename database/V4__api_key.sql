@@ -0,0 +1,20 @@
+-- service-to-service 호출용 API key
+-- key_hash     : API key 의 sha256 (원문은 생성 응답에서 한 번만 보여줌)
+-- prefix       : 목록에서 key 를 구분하기 위한 원문 앞부분
+-- owner        : key 를 사용하는 서비스 / 담당자
+-- last_used_at : 인증에 마지막으로 사용된 시각 (분 단위로만 갱신)
+CREATE TABLE api_key
+(
+    id           bigserial     NOT NULL PRIMARY KEY,
+    uuid         uuid          NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    created_at   timestamptz   NOT NULL        DEFAULT now(),
+--
+    name         varchar(64)   NOT NULL,
+    owner        varchar(128)  NOT NULL,
+    prefix       varchar(16)   NOT NULL,
+    key_hash     varchar(64)   NOT NULL UNIQUE,
+    scopes       varchar(64)[] NOT NULL        DEFAULT '{}',
+    expires_at   timestamptz   NULL,
+    last_used_at timestamptz   NULL,
+    revoked_at   timestamptz   NULL
+);
@@ -0,0 +1,21 @@
+-- version : 낙관적 동시성 제어용 수정 번호 (ETag / If-Match). 수정될 때마다 1 증가
+ALTER TABLE appuser
+    ADD COLUMN version bigint NOT NULL DEFAULT 1;
+
+CREATE
+OR REPLACE FUNCTION fn_increment_version()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.version
+= OLD.version + 1;
+RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+
+CREATE TRIGGER tr_appuser_increment_version
+    BEFORE UPDATE
+    ON appuser
+    FOR EACH ROW
+    EXECUTE PROCEDURE fn_increment_version();
@@ -0,0 +1,26 @@
+-- name: CreateApiKey :one
+INSERT INTO api_key (name, owner, prefix, key_hash, scopes, expires_at)
+VALUES (@name, @owner, @prefix, @key_hash, @scopes::varchar[], @expires_at)
+RETURNING *;
+
+-- name: GetApiKeyByHash :one
+SELECT *
+FROM api_key
+WHERE key_hash = @key_hash;
+
+-- name: ListApiKeys :many
+SELECT *
+FROM api_key
+ORDER BY id DESC;
+
+-- name: RevokeApiKey :one
+UPDATE api_key
+SET revoked_at = COALESCE(revoked_at, now())
+WHERE uuid = @uuid
+RETURNING *;
+
+-- name: TouchApiKey :execrows
+UPDATE api_key
+SET last_used_at = now()
+WHERE id = @id
+  AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute');
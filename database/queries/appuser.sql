@@ -7,6 +7,16 @@ SELECT *
 FROM appuser
 WHERE name = $1;
 
+-- name: GetAppuser :one
+SELECT *
+FROM appuser
+WHERE uuid = @uuid;
+
+-- name: GetAppuserByID :one
+SELECT *
+FROM appuser
+WHERE id = @id;
+
 -- name: SearchAppusers :many
 SELECT *
 FROM appuser
@@ -14,8 +24,25 @@ WHERE (@uuid::varchar IS NULL OR uuid = CAST(@uuid AS UUID))
   AND (@name::varchar IS NULL OR name = @name)
   AND (@gender::enum_gender IS NULL OR gender = @gender)
   AND (@withdraw::boolean IS NULL OR withdraw = @withdraw)
+  AND (@cursor_id::bigint IS NULL
+    OR (@cursor_key::text = 'id' AND @cursor_dir::text = 'ASC' AND id > @cursor_id)
+    OR (@cursor_key::text = 'id' AND @cursor_dir::text = 'DESC' AND id < @cursor_id)
+    OR (@cursor_key::text = 'name' AND @cursor_dir::text = 'ASC' AND (name, id) > (@cursor_name::varchar, @cursor_id))
+    OR (@cursor_key::text = 'name' AND @cursor_dir::text = 'DESC' AND (name, id) < (@cursor_name::varchar, @cursor_id))
+    OR (@cursor_key::text = 'birthday' AND @cursor_dir::text = 'ASC' AND (birthday, id) > (@cursor_birthday::date, @cursor_id))
+    OR (@cursor_key::text = 'birthday' AND @cursor_dir::text = 'DESC' AND (birthday, id) < (@cursor_birthday::date, @cursor_id))
+    OR (@cursor_key::text = 'created_at' AND @cursor_dir::text = 'ASC' AND (created_at, id) > (@cursor_created_at::timestamptz, @cursor_id))
+    OR (@cursor_key::text = 'created_at' AND @cursor_dir::text = 'DESC' AND (created_at, id) < (@cursor_created_at::timestamptz, @cursor_id)))
           ? 1 = @options::text;
 
+-- name: CountAppusers :one
+SELECT count(*)
+FROM appuser
+WHERE (@uuid::varchar IS NULL OR uuid = CAST(@uuid AS UUID))
+  AND (@name::varchar IS NULL OR name = @name)
+  AND (@gender::enum_gender IS NULL OR gender = @gender)
+  AND (@withdraw::boolean IS NULL OR withdraw = @withdraw);
+
 -- name: CreateAppuser :one
 INSERT INTO appuser (name, birthday, gender, withdraw)
 VALUES ($1, $2, $3, $4)
@@ -23,9 +50,45 @@ RETURNING *;
 
 -- name: UpdateAppuser :one
 UPDATE appuser
-SET name     = $2,
-    birthday = $3,
-    gender   = $4,
-    withdraw = $5
-WHERE appuser.uuid = $1
-RETURNING *;
\ No newline at end of file
+SET name         = @name,
+    birthday     = @birthday,
+    gender       = @gender,
+    withdraw     = @withdraw,
+    withdrawn_at = CASE WHEN @withdraw THEN COALESCE(withdrawn_at, now()) END
+WHERE appuser.uuid = @uuid
+  AND (@expected_version::bigint IS NULL OR version = @expected_version)
+RETURNING *;
+
+-- name: PatchAppuser :one
+UPDATE appuser
+SET name         = COALESCE(@name::varchar, name),
+    birthday     = COALESCE(@birthday::date, birthday),
+    gender       = COALESCE(@gender::enum_gender, gender),
+    withdraw     = COALESCE(@withdraw::boolean, withdraw),
+    withdrawn_at = CASE WHEN COALESCE(@withdraw::boolean, withdraw) THEN COALESCE(withdrawn_at, now()) END,
+    role         = COALESCE(@role::varchar[], role)
+WHERE appuser.uuid = @uuid
+  AND (@expected_version::bigint IS NULL OR version = @expected_version)
+RETURNING *;
+
+-- name: DeleteAppuser :execrows
+DELETE
+FROM appuser
+WHERE uuid = @uuid;
+
+-- name: WithdrawAppuser :one
+UPDATE appuser
+SET withdraw     = true,
+    withdrawn_at = COALESCE(withdrawn_at, now())
+WHERE appuser.uuid = @uuid
+RETURNING *;
+
+-- name: AnonymizeWithdrawnAppusers :many
+UPDATE appuser
+SET name          = '',
+    birthday      = DATE '1900-01-01',
+    anonymized_at = now()
+WHERE withdraw = true
+  AND anonymized_at IS NULL
+  AND withdrawn_at < @withdrawn_before::timestamptz
+RETURNING uuid;
\ No newline at end of file
@@ -0,0 +1,43 @@
+-- name: GetAppuserCredential :one
+SELECT *
+FROM appuser_credential
+WHERE login = @login;
+
+-- name: CreateAppuserCredential :one
+INSERT INTO appuser_credential (appuser_id, login, password_hash)
+VALUES (@appuser_id, @login, @password_hash)
+RETURNING *;
+
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_token (appuser_id, family, token_hash, expires_at)
+VALUES (@appuser_id, @family, @token_hash, @expires_at)
+RETURNING *;
+
+-- name: GetRefreshTokenForUpdate :one
+SELECT *
+FROM refresh_token
+WHERE token_hash = @token_hash
+    FOR UPDATE;
+
+-- name: UseRefreshToken :execrows
+UPDATE refresh_token
+SET used_at = now()
+WHERE id = @id
+  AND used_at IS NULL;
+
+-- name: RevokeRefreshTokenFamily :execrows
+UPDATE refresh_token
+SET revoked_at = now()
+WHERE family = @family
+  AND revoked_at IS NULL;
+
+-- name: DeleteExpiredRefreshTokens :execrows
+DELETE
+FROM refresh_token
+WHERE expires_at < @expired_before::timestamptz;
+
+-- name: RevokeAppuserRefreshTokens :execrows
+UPDATE refresh_token
+SET revoked_at = now()
+WHERE appuser_id = (SELECT id FROM appuser WHERE uuid = @uuid)
+  AND revoked_at IS NULL;
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 )
 
@@ -24,6 +25,11 @@ func main() {
 	// Create claims
 	claims := jwt.MapClaims{
 		"uuid": "12956e54-503d-46f1-8b9b-7cf304fba601",
+		// 기기별 세션 ID (없으면 사용자의 모든 기기가 세션 하나를 공유)
+		"jti": uuid.NewString(),
+		// 개발용 토큰은 모든 API 를 호출할 수 있도록 admin role 부여
+		"scope": "appuser:read appuser:write",
+		"roles": []string{"admin"},
 		"exp":  time.Now().Add(time.Hour * 24 * 7).Unix(), // 7 days from now
 		"iat":  time.Now().Unix(),
 	}
@@ -41,6 +47,9 @@ func main() {
 	fmt.Println("\nSecret used:", jwtSecret)
 	fmt.Println("\nClaims:")
 	fmt.Printf("  uuid: %v\n", claims["uuid"])
+	fmt.Printf("  jti: %v\n", claims["jti"])
+	fmt.Printf("  scope: %v\n", claims["scope"])
+	fmt.Printf("  roles: %v\n", claims["roles"])
 	fmt.Printf("  exp: %v (expires at: %v)\n", claims["exp"], time.Unix(int64(claims["exp"].(int64)), 0))
 	fmt.Printf("  iat: %v (issued at: %v)\n", claims["iat"], time.Unix(int64(claims["iat"].(int64)), 0))
 	fmt.Println("\nToken:")
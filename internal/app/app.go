@@ -13,9 +13,14 @@ import (
 
 	"fiber-boilerplate/internal/app/config"
 	"fiber-boilerplate/internal/app/handlers"
+	"fiber-boilerplate/internal/app/jobs"
 	"fiber-boilerplate/internal/app/middleware"
 	"fiber-boilerplate/internal/models"
+	"fiber-boilerplate/internal/pkg/database"
 	"fiber-boilerplate/internal/pkg/logging"
+	"fiber-boilerplate/internal/pkg/realtime"
+	"fiber-boilerplate/internal/pkg/revocation"
+	"fiber-boilerplate/internal/pkg/session"
 	"fiber-boilerplate/internal/pkg/setting"
 
 	"github.com/gofiber/fiber/v2"
@@ -29,13 +34,28 @@ func Start() {
 	}
 
 	config.Setup()
+	database.ConfigurePubSub(database.PubSubConfigBlock{
+		Buffer:       config.Server.PubSub.Buffer,
+		SlowConsumer: config.Server.PubSub.SlowConsumer,
+	})
 	models.Setup()
+	revocation.Setup(time.Duration(config.Server.JWT.RevocationTTL) * time.Second)
 
-	f := fiber.New()
+	f := fiber.New(fiber.Config{
+		ErrorHandler: handlers.ErrorHandler,
+	})
 
 	middleware.Register(f)
 	handlers.Register(f)
 
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	jobs.Start(jobCtx)
+	// appuser 가 바뀌면 캐시된 세션 사용자 정보 무효화
+	session.Follow(middleware.ContextKeyStore)
+	// SSE, WebSocket 이벤트 hub. 종료할 때 열린 stream 과 WebSocket 을 먼저 닫아 graceful shutdown 이 기다리지 않도록 jobCtx 사용
+	realtime.Setup(jobCtx)
+
 	// Start server
 	go func() {
 		addr := fmt.Sprintf(":%d", setting.Runtime.Port)
@@ -53,6 +73,7 @@ func Start() {
 
 	<-sig
 	logging.Info("Shutdown signal received, starting graceful shutdown...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.Server.GracefulTimeout)*time.Second)
 	defer cancel()
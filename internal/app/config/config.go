@@ -13,6 +13,24 @@ type ServerBlock struct {
 	GracefulTimeout    int    `env:"GRACEFUL_TIMEOUT" envDefault:"10" json:"gracefulTimeout,omitempty"`
 	LogRequestsEnabled bool   `env:"LOG_REQUESTS_ENABLED" envDefault:"true" json:"logRequestsEnabled,omitempty"`
 	JwtSecret          string `env:"JWT_SECRET" json:"jwtSecret,omitempty"`
+	// JWT : 비대칭 서명(RS/PS/ES/EdDSA) 검증. JWT_SECRET 과 함께 쓸 수 있음
+	JWT struct {
+		PublicKeys  []string `env:"JWT_PUBLIC_KEYS" envSeparator:"," json:"publicKeys,omitempty"`
+		JWKS        string   `env:"JWT_JWKS" json:"jwks,omitempty"`
+		JWKSRefresh int      `env:"JWT_JWKS_REFRESH" envDefault:"300" json:"jwksRefresh,omitempty"`
+		Issuer      string   `env:"JWT_ISSUER" json:"issuer,omitempty"`
+		Audience    []string `env:"JWT_AUDIENCE" envSeparator:"," json:"audience,omitempty"`
+		// 직접 발급하는 토큰의 유효 기간(초). 발급에는 JWT_SECRET 이 필요
+		AccessTTL  int `env:"JWT_ACCESS_TTL" envDefault:"900" json:"accessTTL,omitempty"`
+		RefreshTTL int `env:"JWT_REFRESH_TTL" envDefault:"1209600" json:"refreshTTL,omitempty"`
+		// 직접 발급하는 access token 의 scope. roles 는 appuser.role
+		IssuedScopes         []string `env:"JWT_ISSUED_SCOPES" envSeparator:" " envDefault:"appuser:read appuser:write" json:"issuedScopes,omitempty"`
+		RefreshPurgeInterval int      `env:"JWT_REFRESH_PURGE_INTERVAL" envDefault:"3600" json:"refreshPurgeInterval,omitempty"`
+		// 폐기 목록 보관 기간(초). 발급되는 토큰의 최대 유효 기간 이상이어야 함
+		RevocationTTL int `env:"JWT_REVOCATION_TTL" envDefault:"604800" json:"revocationTTL,omitempty"`
+	} `json:"jwt"`
+	// ResponseValidation : off / log / fail. 비어 있으면 local, development 에서 log
+	ResponseValidation string `env:"OAPI_RESPONSE_VALIDATION" json:"responseValidation,omitempty"`
 	CORS               struct {
 		Enabled          bool     `env:"CORS_ENABLED" envDefault:"true" json:"enabled,omitempty"`
 		AllowOrigins     []string `env:"CORS_ALLOW_ORIGINS" envSeparator:"," envDefault:"*" json:"allowOrigins,omitempty"`
@@ -22,6 +40,29 @@ type ServerBlock struct {
 		ExposeHeaders    []string `env:"CORS_EXPOSE_HEADERS" envSeparator:"," envDefault:"" json:"exposeHeaders,omitempty"`
 		MaxAge           int      `env:"CORS_MAX_AGE" envSeparator:"," envDefault:"0" json:"maxAge,omitempty"`
 	} `json:"cors"`
+	// Session : JWT 세션 저장소 만료(초). idle 은 사용할 때마다 연장, max lifetime 은 생성 시각 기준 (0 이면 제한 없음)
+	Session struct {
+		IdleTimeout   int `env:"SESSION_IDLE_TIMEOUT" envDefault:"7200" json:"idleTimeout,omitempty"`
+		MaxLifetime   int `env:"SESSION_MAX_LIFETIME" envDefault:"86400" json:"maxLifetime,omitempty"`
+		TouchInterval int `env:"SESSION_TOUCH_INTERVAL" envDefault:"60" json:"touchInterval,omitempty"`
+		// Codec : gob, json, msgpack. json/msgpack 은 다른 언어의 관리 도구에서도 읽을 수 있음
+		Codec string `env:"SESSION_CODEC" envDefault:"json" json:"codec,omitempty"`
+	} `json:"session"`
+	// PubSub : 구독자마다 쌓아 둘 수 있는 메시지 수와 가득 찼을 때 drop (버림) / disconnect (구독 종료).
+	// Redis 가 없을 때의 in-process pub/sub 과 SSE 구독자에 적용
+	PubSub struct {
+		Buffer       int    `env:"PUBSUB_BUFFER" envDefault:"256" json:"buffer,omitempty"`
+		SlowConsumer string `env:"PUBSUB_SLOW_CONSUMER" envDefault:"drop" json:"slowConsumer,omitempty"`
+	} `json:"pubsub"`
+	// SSE : EventSource 용 ticket 의 유효 기간(초). 토큰의 만료 시각을 넘지 않음
+	SSE struct {
+		TicketTTL int `env:"SSE_TICKET_TTL" envDefault:"60" json:"ticketTTL,omitempty"`
+	} `json:"sse"`
+	// Withdraw : 탈퇴 사용자 개인정보 보관 기간(일) / 익명화 작업 주기(초). 0 이하면 작업 비활성화
+	Withdraw struct {
+		RetentionDays     int `env:"WITHDRAW_RETENTION_DAYS" envDefault:"30" json:"retentionDays,omitempty"`
+		AnonymizeInterval int `env:"WITHDRAW_ANONYMIZE_INTERVAL" envDefault:"3600" json:"anonymizeInterval,omitempty"`
+	} `json:"withdraw"`
 }
 
 // Server : admin server
@@ -35,8 +76,8 @@ func Setup() {
 	}
 
 	// Validate required configuration
-	if Server.JwtSecret == "" {
-		panic("JWT_SECRET environment variable is required")
+	if Server.JwtSecret == "" && len(Server.JWT.PublicKeys) == 0 && Server.JWT.JWKS == "" {
+		panic("JWT_SECRET, JWT_PUBLIC_KEYS or JWT_JWKS environment variable is required")
 	}
 
 	// Setup database configuration
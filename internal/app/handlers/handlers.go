@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+
 	v1 "fiber-boilerplate/internal/app/handlers/v1"
 	api "fiber-boilerplate/internal/generated/serviceapi"
 
@@ -15,6 +17,17 @@ func SendError(ctx *fiber.Ctx, code int, errs ...error) error {
 	return v1.SendError(ctx, code, errs...)
 }
 
+// ErrorHandler : handler 가 반환한 error 를 problem 으로 응답 (fiber.Config.ErrorHandler)
+func ErrorHandler(ctx *fiber.Ctx, err error) error {
+	code := fiber.StatusInternalServerError
+	var fiberError *fiber.Error
+	if errors.As(err, &fiberError) {
+		code = fiberError.Code
+	}
+
+	return v1.SendError(ctx, code, err)
+}
+
 // SendResponse :
 func SendResponse(ctx *fiber.Ctx, code int, response interface{}) error {
 	return v1.SendResponse(ctx, code, response)
@@ -24,12 +37,24 @@ func (h APIHandlerBlock) GetPing(ctx *fiber.Ctx) error {
 	return v1.GetPing(ctx)
 }
 
-func (h APIHandlerBlock) SseOpen(ctx *fiber.Ctx) error {
-	return v1.SseOpen(ctx)
+func (h APIHandlerBlock) GetHealth(ctx *fiber.Ctx) error {
+	return v1.GetHealth(ctx)
+}
+
+func (h APIHandlerBlock) SseOpen(ctx *fiber.Ctx, params api.SseOpenParams) error {
+	return v1.SseOpen(ctx, params)
+}
+
+func (h APIHandlerBlock) IssueSseTicket(ctx *fiber.Ctx) error {
+	return v1.IssueSseTicket(ctx)
+}
+
+func (h APIHandlerBlock) PublishEvent(ctx *fiber.Ctx) error {
+	return v1.PublishEvent(ctx)
 }
 
-func (h APIHandlerBlock) SseClose(ctx *fiber.Ctx) error {
-	return v1.SseClose(ctx)
+func (h APIHandlerBlock) WsOpen(ctx *fiber.Ctx, params api.WsOpenParams) error {
+	return v1.WsOpen(ctx, params)
 }
 
 func (h APIHandlerBlock) ListAppusers(ctx *fiber.Ctx, params api.ListAppusersParams) error {
@@ -43,3 +68,63 @@ func (h APIHandlerBlock) CreateAppuser(ctx *fiber.Ctx) error {
 func (h APIHandlerBlock) UpdateAppuser(ctx *fiber.Ctx) error {
 	return v1.UpdateAppuser(ctx)
 }
+
+func (h APIHandlerBlock) GetAppuser(ctx *fiber.Ctx, uuid api.UuidPathParam) error {
+	return v1.GetAppuser(ctx, uuid)
+}
+
+func (h APIHandlerBlock) PatchAppuser(ctx *fiber.Ctx, uuid api.UuidPathParam) error {
+	return v1.PatchAppuser(ctx, uuid)
+}
+
+func (h APIHandlerBlock) DeleteAppuser(ctx *fiber.Ctx, uuid api.UuidPathParam) error {
+	return v1.DeleteAppuser(ctx, uuid)
+}
+
+func (h APIHandlerBlock) WithdrawAppuser(ctx *fiber.Ctx, uuid api.UuidPathParam) error {
+	return v1.WithdrawAppuser(ctx, uuid)
+}
+
+func (h APIHandlerBlock) Login(ctx *fiber.Ctx) error {
+	return v1.Login(ctx)
+}
+
+func (h APIHandlerBlock) RefreshToken(ctx *fiber.Ctx) error {
+	return v1.RefreshToken(ctx)
+}
+
+func (h APIHandlerBlock) Logout(ctx *fiber.Ctx) error {
+	return v1.Logout(ctx)
+}
+
+func (h APIHandlerBlock) RevokeToken(ctx *fiber.Ctx) error {
+	return v1.RevokeToken(ctx)
+}
+
+func (h APIHandlerBlock) RevokeAppuserTokens(ctx *fiber.Ctx, uuid api.UuidPathParam) error {
+	return v1.RevokeAppuserTokens(ctx, uuid)
+}
+
+func (h APIHandlerBlock) CreateApiKey(ctx *fiber.Ctx) error {
+	return v1.CreateApiKey(ctx)
+}
+
+func (h APIHandlerBlock) ListApiKeys(ctx *fiber.Ctx) error {
+	return v1.ListApiKeys(ctx)
+}
+
+func (h APIHandlerBlock) RevokeApiKey(ctx *fiber.Ctx, uuid api.UuidPathParam) error {
+	return v1.RevokeApiKey(ctx, uuid)
+}
+
+func (h APIHandlerBlock) ListSessions(ctx *fiber.Ctx) error {
+	return v1.ListSessions(ctx)
+}
+
+func (h APIHandlerBlock) RevokeSession(ctx *fiber.Ctx, sessionId api.SessionIdPathParam) error {
+	return v1.RevokeSession(ctx, sessionId)
+}
+
+func (h APIHandlerBlock) RevokeOtherSessions(ctx *fiber.Ctx) error {
+	return v1.RevokeOtherSessions(ctx)
+}
@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"fiber-boilerplate/internal/defs"
+	api "fiber-boilerplate/internal/generated/serviceapi"
+	"fiber-boilerplate/internal/models"
+	"fiber-boilerplate/internal/pkg/revocation"
+	"fiber-boilerplate/internal/pkg/session"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RevokeToken : jti 로 토큰 하나 폐기
+func RevokeToken(ctx *fiber.Ctx) error {
+	var body api.RevokeTokenRequest
+	if err := ctx.BodyParser(&body); err != nil {
+		return SendError(ctx, http.StatusBadRequest, defs.ErrMalformedBody.Wrap(err))
+	}
+
+	var expiresAt time.Time
+	if body.ExpiresAt != nil {
+		expiresAt = time.UnixMilli(*body.ExpiresAt)
+	}
+	if err := revocation.RevokeToken(ctx.Context(), body.Jti, expiresAt); err != nil {
+		return SendError(ctx, http.StatusServiceUnavailable, defs.ErrRevocationWrite.Wrap(err))
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+// RevokeAppuserTokens : 지금까지 발급된 사용자의 토큰을 모두 폐기 (강제 로그아웃)
+func RevokeAppuserTokens(ctx *fiber.Ctx, uuid api.UuidPathParam) error {
+	_, err := models.Appuser.GetAppuser(ctx.Context(), uuid.String())
+	if errors.Is(err, sql.ErrNoRows) {
+		return SendError(ctx, http.StatusNotFound, defs.ErrAppuserNotFound.WithMessage("appuser not found: %s", uuid))
+	}
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to get appuser: %w", err))
+	}
+
+	// access token 을 먼저 막고, 새 access token 을 받지 못하도록 refresh token 폐기
+	if err := revocation.RevokeAppuser(ctx.Context(), uuid.String(), time.Now()); err != nil {
+		return SendError(ctx, http.StatusServiceUnavailable, defs.ErrRevocationWrite.Wrap(err))
+	}
+	if _, err := models.RefreshToken.RevokeAppuserRefreshTokens(nil, ctx.Context(), uuid.String()); err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to revoke refresh tokens: %w", err))
+	}
+	if err := session.Invalidate(ctx, uuid.String()); err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to invalidate session: %w", err))
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
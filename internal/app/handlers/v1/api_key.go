@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"fiber-boilerplate/internal/defs"
+	api "fiber-boilerplate/internal/generated/serviceapi"
+	"fiber-boilerplate/internal/models"
+	"fiber-boilerplate/internal/pkg/auth"
+
+	"github.com/gofiber/fiber/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+// CreateApiKey : 원문 key 는 저장하지 않으므로 이 응답에서만 반환
+func CreateApiKey(ctx *fiber.Ctx) error {
+	var body api.CreateApiKeyRequest
+	if err := ctx.BodyParser(&body); err != nil {
+		return SendError(ctx, http.StatusBadRequest, defs.ErrMalformedBody.Wrap(err))
+	}
+
+	var expiresAt null.Time
+	if body.ExpiresAt != nil {
+		expiresAt = null.TimeFrom(time.UnixMilli(*body.ExpiresAt))
+		if !expiresAt.Time.After(time.Now()) {
+			return SendError(ctx, http.StatusBadRequest, defs.ErrValidationFailed.WithViolations(defs.Violation{
+				Field:   "body.expiresAt",
+				Message: "expiresAt must be in the future",
+			}))
+		}
+	}
+
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to generate api key: %w", err))
+	}
+
+	entity, err := models.ApiKey.CreateApiKey(nil, ctx.Context(), models.CreateApiKeyParams{
+		Name:      null.StringFrom(body.Name),
+		Owner:     null.StringFrom(body.Owner),
+		Prefix:    null.StringFrom(prefix),
+		KeyHash:   null.StringFrom(hash),
+		Scopes:    body.Scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to create api key: %w", err))
+	}
+
+	resp := apiKeyResponse(entity)
+	resp.Key = &key
+	return SendResponse(ctx, http.StatusCreated, resp)
+}
+
+// ListApiKeys :
+func ListApiKeys(ctx *fiber.Ctx) error {
+	list, err := models.ApiKey.ListApiKeys(ctx.Context())
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to list api keys: %w", err))
+	}
+
+	apiKeys := make([]api.ApiKey, 0, len(list))
+	for _, entity := range list {
+		apiKeys = append(apiKeys, *apiKeyResponse(entity))
+	}
+
+	return SendResponse(ctx, http.StatusOK, &api.ApiKeyListInfo{ApiKeys: apiKeys})
+}
+
+// RevokeApiKey : 이미 폐기된 key 면 폐기 시각을 유지
+func RevokeApiKey(ctx *fiber.Ctx, uuid api.UuidPathParam) error {
+	_, err := models.ApiKey.RevokeApiKey(nil, ctx.Context(), uuid.String())
+	if errors.Is(err, sql.ErrNoRows) {
+		return SendError(ctx, http.StatusNotFound, defs.ErrRecordNotFound.WithMessage("api key not found: %s", uuid))
+	}
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to revoke api key: %w", err))
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+// apiKeyResponse : key 원문과 hash 는 포함하지 않음
+func apiKeyResponse(entity models.ApiKeyBlock) *api.ApiKey {
+	entityResp := EntityResponse(entity)
+	scopes := append([]string{}, entity.Scopes...)
+	return &api.ApiKey{
+		CreatedAt:  entityResp.CreatedAt,
+		UUID:       entityResp.UUID,
+		Name:       entity.Name.String,
+		Owner:      entity.Owner.String,
+		Prefix:     entity.Prefix.String,
+		Scopes:     scopes,
+		ExpiresAt:  models.NullableTS(entity.ExpiresAt),
+		LastUsedAt: models.NullableTS(entity.LastUsedAt),
+		RevokedAt:  models.NullableTS(entity.RevokedAt),
+	}
+}
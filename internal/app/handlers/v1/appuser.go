@@ -1,11 +1,19 @@
 package v1
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
+	"fiber-boilerplate/internal/defs"
 	api "fiber-boilerplate/internal/generated/serviceapi"
 	"fiber-boilerplate/internal/models"
+	"fiber-boilerplate/internal/pkg/auth"
+	"fiber-boilerplate/internal/pkg/authz"
+	"fiber-boilerplate/internal/pkg/session"
 	"fiber-boilerplate/internal/pkg/util"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,10 +23,25 @@ import (
 func CreateAppuser(ctx *fiber.Ctx) error {
 	var body api.CreateAppuserRequest
 	if err := ctx.BodyParser(&body); err != nil {
-		return SendError(ctx, http.StatusBadRequest, fmt.Errorf("failed to parse request body: %w", err))
+		return SendError(ctx, http.StatusBadRequest, defs.ErrMalformedBody.Wrap(err))
 	}
 
-	entity, err := models.Appuser.CreateAppuser(nil, ctx.Context(), models.CreateAppuserParams{
+	// login 과 password 는 함께 지정해야 함
+	if (body.Login == nil) != (body.Password == nil) {
+		return SendError(ctx, http.StatusBadRequest, defs.ErrValidationFailed.WithViolations(defs.Violation{
+			Field:   "body.login",
+			Message: "login and password must be provided together",
+		}))
+	}
+
+	/* Tx Begin */
+	tx, qctx, err := models.SQL.BeginxContext(ctx.Context())
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	defer tx.Rollback()
+	qtx := models.New(tx)
+	entity, err := models.Appuser.CreateAppuser(qtx, qctx, models.CreateAppuserParams{
 		Name:     null.StringFrom(body.Name),
 		Birthday: null.TimeFrom(util.Time.ToTimeFromOapiDate(body.Birthday)),
 		Gender:   models.GenderToNullString(string(body.Gender)),
@@ -28,42 +51,122 @@ func CreateAppuser(ctx *fiber.Ctx) error {
 		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to create appuser: %w", err))
 	}
 
-	entityResp := EntityResponse(entity)
-	return SendResponse(ctx, http.StatusOK, &api.Appuser{
-		CreatedAt:  entityResp.CreatedAt,
-		ModifiedAt: entityResp.ModifiedAt,
-		UUID:       entityResp.UUID,
-		Birthday:   util.Time.ToOapiDate(entity.Birthday.Time),
-		Gender:     entity.Gender.String,
-		Name:       entity.Name.String,
-		Withdraw:   entity.Withdraw.Bool,
-	})
+	if body.Login != nil {
+		passwordHash, err := auth.HashPassword(*body.Password)
+		if err != nil {
+			return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to hash password: %w", err))
+		}
+		// 중복 login 은 unique violation 으로 409
+		_, err = models.AppuserCredential.CreateAppuserCredential(qtx, qctx, models.CreateAppuserCredentialParams{
+			AppuserID:    entity.ID,
+			Login:        null.StringFrom(*body.Login),
+			PasswordHash: null.StringFrom(passwordHash),
+		})
+		if err != nil {
+			return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to create credential: %w", err))
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction: %w", err))
+	}
+	/* Tx Commit */
+
+	return sendAppuser(ctx, entity)
 }
 
 func ListAppusers(ctx *fiber.Ctx, params api.ListAppusersParams) error {
-	sorting, pagination, err := EntityListParam(params.Sorting, params.Pagination)
+	sorting, pagination, err := EntityListParam(params.Sorting, params.Pagination, models.SearchAppusersSortColumns)
 	if err != nil {
-		return SendError(ctx, http.StatusBadRequest, fmt.Errorf("invalid list parameters: %w", err))
+		return SendError(ctx, http.StatusBadRequest, defs.ErrInvalidParameter.WithMessage("invalid list parameters: %v", err))
+	}
+	options, err := models.MakeListOptions(sorting, pagination).Parameterize()
+	if err != nil {
+		return SendError(ctx, http.StatusBadRequest, err)
 	}
 
-	list, err := models.Appuser.SearchAppusers(ctx.Context(), models.SearchAppusersParams{
+	searchParams := models.SearchAppusersParams{
 		UUID:     null.StringFromPtr(params.Uuid),
 		Name:     null.StringFromPtr(params.Name),
 		Gender:   null.StringFromPtr(params.Gender),
 		Withdraw: null.BoolFromPtr(params.Withdraw),
-		Options:  models.MakeListOptions(sorting, pagination).Parameterize(),
+		Options:  options,
+	}
+	if pagination.Cursor != nil {
+		if err := appuserCursorParams(&searchParams, pagination.Cursor); err != nil {
+			return SendError(ctx, http.StatusBadRequest, defs.ErrInvalidParameter.WithMessage("invalid list parameters: %v", err))
+		}
+	}
+
+	/* Tx Begin */
+	// 목록과 total 을 같은 snapshot 에서 조회
+	tx, qctx, err := models.SQL.BeginTxContext(ctx.Context(), &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
 	})
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	defer tx.Rollback()
+	qtx := models.New(tx)
+	list, err := models.Appuser.SearchAppusers(qtx, qctx, searchParams)
 	if err != nil {
 		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to search appusers: %w", err))
 	}
 
-	return SendResponse(ctx, http.StatusOK, list)
+	total, err := models.Appuser.CountAppusers(qtx, qctx, models.CountAppusersParams{
+		UUID:     searchParams.UUID,
+		Name:     searchParams.Name,
+		Gender:   searchParams.Gender,
+		Withdraw: searchParams.Withdraw,
+	})
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to count appusers: %w", err))
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction: %w", err))
+	}
+	/* Tx Commit */
+
+	var nextCursor *string
+	if pagination.Keyset && len(list) > pagination.Limit {
+		list = list[:pagination.Limit]
+		encoded := appuserCursor(sorting.Orders[0], list[len(list)-1]).Encode()
+		nextCursor = &encoded
+	}
+
+	appusers := make([]api.Appuser, 0, len(list))
+	for i := range list {
+		appusers = append(appusers, *appuserResponse(list[i]))
+	}
+
+	return SendResponse(ctx, http.StatusOK, &api.AppuserListInfo{
+		Total:      &total,
+		Appusers:   &appusers,
+		NextCursor: nextCursor,
+	})
 }
 
 func UpdateAppuser(ctx *fiber.Ctx) error {
 	var body api.Appuser
 	if err := ctx.BodyParser(&body); err != nil {
-		return SendError(ctx, http.StatusBadRequest, fmt.Errorf("failed to parse request body: %w", err))
+		return SendError(ctx, http.StatusBadRequest, defs.ErrMalformedBody.Wrap(err))
+	}
+
+	if err := authorizeAppuser(ctx, body.UUID); err != nil {
+		return SendError(ctx, http.StatusForbidden, err)
+	}
+
+	// 클라이언트가 마지막으로 본 version. If-Match 가 body 의 version 보다 우선
+	expected, err := IfMatchVersion(ctx)
+	if err != nil {
+		return SendError(ctx, http.StatusBadRequest, err)
+	}
+	if !expected.Valid {
+		expected = null.IntFromPtr(body.Version)
 	}
 
 	/* Tx Begin */
@@ -74,12 +177,16 @@ func UpdateAppuser(ctx *fiber.Ctx) error {
 	defer tx.Rollback()
 	qtx := models.New(tx)
 	entity, err := models.Appuser.UpdateAppuser(qtx, qctx, models.UpdateAppuserParams{
-		UUID:     null.StringFrom(body.UUID),
-		Name:     null.StringFrom(body.Name),
-		Birthday: null.TimeFrom(util.Time.ToTimeFromOapiDate(body.Birthday)),
-		Gender:   models.GenderToNullString(body.Gender),
-		Withdraw: null.BoolFrom(body.Withdraw),
+		UUID:            null.StringFrom(body.UUID),
+		Name:            null.StringFrom(body.Name),
+		Birthday:        null.TimeFrom(util.Time.ToTimeFromOapiDate(body.Birthday)),
+		Gender:          models.GenderToNullString(body.Gender),
+		Withdraw:        null.BoolFrom(body.Withdraw),
+		ExpectedVersion: expected,
 	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return appuserUpdateMiss(ctx, body.UUID)
+	}
 	if err != nil {
 		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to update appuser: %w", err))
 	}
@@ -90,14 +197,226 @@ func UpdateAppuser(ctx *fiber.Ctx) error {
 	}
 	/* Tx Commit */
 
+	return sendAppuser(ctx, entity)
+}
+
+func GetAppuser(ctx *fiber.Ctx, uuid api.UuidPathParam) error {
+	if err := authorizeAppuser(ctx, uuid.String()); err != nil {
+		return SendError(ctx, http.StatusForbidden, err)
+	}
+
+	entity, err := models.Appuser.GetAppuser(ctx.Context(), uuid.String())
+	if errors.Is(err, sql.ErrNoRows) {
+		return SendError(ctx, http.StatusNotFound, defs.ErrAppuserNotFound.WithMessage("appuser not found: %s", uuid))
+	}
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to get appuser: %w", err))
+	}
+
+	return sendAppuser(ctx, entity)
+}
+
+func PatchAppuser(ctx *fiber.Ctx, uuid api.UuidPathParam) error {
+	var body api.PatchAppuserRequest
+	if err := ctx.BodyParser(&body); err != nil {
+		return SendError(ctx, http.StatusBadRequest, defs.ErrMalformedBody.Wrap(err))
+	}
+
+	if err := authorizeAppuser(ctx, uuid.String()); err != nil {
+		return SendError(ctx, http.StatusForbidden, err)
+	}
+	// role 변경은 자신의 권한을 올릴 수 있으므로 admin 만 가능
+	if body.Roles != nil && !authz.FromContext(ctx).HasRole(authz.RoleAdmin) {
+		return SendError(ctx, http.StatusForbidden, defs.ErrInsufficientPermission.WithMessage("only %s can change roles", authz.RoleAdmin))
+	}
+
+	// 생략된 필드는 NULL 로 전달되어 기존 값이 유지됨
+	params := models.PatchAppuserParams{
+		UUID:     null.StringFrom(uuid.String()),
+		Name:     null.StringFromPtr(body.Name),
+		Withdraw: null.BoolFromPtr(body.Withdraw),
+	}
+	if body.Birthday != nil {
+		params.Birthday = null.TimeFrom(util.Time.ToTimeFromOapiDate(*body.Birthday))
+	}
+	if body.Gender != nil {
+		params.Gender = models.GenderToNullString(string(*body.Gender))
+	}
+	if body.Roles != nil {
+		// nil 이면 기존 값 유지이므로 빈 목록은 빈 slice 로 전달
+		params.Role = append([]string{}, *body.Roles...)
+	}
+	// If-Match 가 있으면 그 version 일 때만 수정
+	expected, err := IfMatchVersion(ctx)
+	if err != nil {
+		return SendError(ctx, http.StatusBadRequest, err)
+	}
+	params.ExpectedVersion = expected
+
+	/* Tx Begin */
+	tx, qctx, err := models.SQL.BeginxContext(ctx.Context())
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	defer tx.Rollback()
+	qtx := models.New(tx)
+	entity, err := models.Appuser.PatchAppuser(qtx, qctx, params)
+	if errors.Is(err, sql.ErrNoRows) {
+		return appuserUpdateMiss(ctx, uuid.String())
+	}
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to patch appuser: %w", err))
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction: %w", err))
+	}
+	/* Tx Commit */
+
+	return sendAppuser(ctx, entity)
+}
+
+func DeleteAppuser(ctx *fiber.Ctx, uuid api.UuidPathParam) error {
+	affected, err := models.Appuser.DeleteAppuser(nil, ctx.Context(), uuid.String())
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to delete appuser: %w", err))
+	}
+	if affected == 0 {
+		return SendError(ctx, http.StatusNotFound, defs.ErrAppuserNotFound.WithMessage("appuser not found: %s", uuid))
+	}
+
+	return SendGeneric(ctx, http.StatusOK)
+}
+
+// WithdrawAppuser : 탈퇴 처리 (soft delete). 개인정보는 보관 기간 후 jobs 에서 익명화.
+// commit 후 OnAppuserChanged hook 이 세션에 캐시된 사용자 정보를 버리므로 이미 발급된 토큰은 다음 요청에서 403
+func WithdrawAppuser(ctx *fiber.Ctx, uuid api.UuidPathParam) error {
+	if err := authorizeAppuser(ctx, uuid.String()); err != nil {
+		return SendError(ctx, http.StatusForbidden, err)
+	}
+
+	/* Tx Begin */
+	tx, qctx, err := models.SQL.BeginxContext(ctx.Context())
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	defer tx.Rollback()
+	qtx := models.New(tx)
+	entity, err := models.Appuser.WithdrawAppuser(qtx, qctx, uuid.String())
+	if errors.Is(err, sql.ErrNoRows) {
+		return SendError(ctx, http.StatusNotFound, defs.ErrAppuserNotFound.WithMessage("appuser not found: %s", uuid))
+	}
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to withdraw appuser: %w", err))
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction: %w", err))
+	}
+	/* Tx Commit */
+
+	return sendAppuser(ctx, entity)
+}
+
+// sendAppuser : 단일 appuser 응답. ETag 는 body hash 대신 entity version 사용
+func sendAppuser(ctx *fiber.Ctx, entity models.AppuserBlock) error {
+	ctx.Set(fiber.HeaderETag, EntityETag(entity.Version))
+	// etag 미들웨어는 ETag 가 이미 있으면 건너뛰므로 If-None-Match 는 여기서 처리
+	if ctx.Method() == fiber.MethodGet && ctx.Fresh() {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
+	return SendResponse(ctx, http.StatusOK, appuserResponse(entity))
+}
+
+// authorizeAppuser : 자기 자신 또는 admin 만 접근 가능.
+// API key 는 특정 사용자에 묶이지 않으므로 scope 검사를 통과했으면 허용
+func authorizeAppuser(ctx *fiber.Ctx, uuid string) error {
+	if authz.FromContext(ctx).HasRole(authz.RoleAdmin) || authz.PrincipalFromContext(ctx).IsAPIKey() {
+		return nil
+	}
+	if caller, _ := session.Claims(ctx)["uuid"].(string); caller != "" && strings.EqualFold(caller, uuid) {
+		return nil
+	}
+
+	return defs.ErrInsufficientPermission.WithMessage("cannot access another appuser")
+}
+
+// appuserUpdateMiss : version 조건으로 수정되지 않았을 때 404 / 409 구분
+func appuserUpdateMiss(ctx *fiber.Ctx, uuid string) error {
+	current, err := models.Appuser.GetAppuser(ctx.Context(), uuid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return SendError(ctx, http.StatusNotFound, defs.ErrAppuserNotFound.WithMessage("appuser not found: %s", uuid))
+	}
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to get appuser: %w", err))
+	}
+
+	ctx.Set(fiber.HeaderETag, EntityETag(current.Version))
+	return SendErrorData(ctx, http.StatusConflict, defs.ErrVersionConflict, appuserResponse(current))
+}
+
+// appuserResponse :
+func appuserResponse(entity models.AppuserBlock) *api.Appuser {
 	entityResp := EntityResponse(entity)
-	return SendResponse(ctx, http.StatusOK, &api.Appuser{
-		CreatedAt:  entityResp.CreatedAt,
-		ModifiedAt: entityResp.ModifiedAt,
-		UUID:       entityResp.UUID,
-		Birthday:   util.Time.ToOapiDate(entity.Birthday.Time),
-		Gender:     entity.Gender.String,
-		Name:       entity.Name.String,
-		Withdraw:   entity.Withdraw.Bool,
-	})
+	roles := append([]string{}, entity.Role...)
+	return &api.Appuser{
+		CreatedAt:   entityResp.CreatedAt,
+		ModifiedAt:  entityResp.ModifiedAt,
+		UUID:        entityResp.UUID,
+		Birthday:    util.Time.ToOapiDate(entity.Birthday.Time),
+		Gender:      entity.Gender.String,
+		Name:        entity.Name.String,
+		Withdraw:    entity.Withdraw.Bool,
+		WithdrawnAt: models.NullableTS(entity.WithdrawnAt),
+		Roles:       &roles,
+		Version:     models.NullableInt64(entity.Version),
+	}
+}
+
+// appuserCursor : 페이지의 마지막 row 로 다음 페이지 cursor 생성
+func appuserCursor(order models.OrderBlock, entity models.AppuserBlock) *models.CursorBlock {
+	cursor := &models.CursorBlock{
+		Key: order.Key,
+		Dir: order.Dir,
+		ID:  entity.ID.Int64,
+	}
+
+	switch order.Key {
+	case "name":
+		cursor.Value = entity.Name.String
+	case "birthday":
+		cursor.Value = entity.Birthday.Time.Format(time.DateOnly)
+	case "created_at":
+		cursor.Value = entity.CreatedAt.Time.Format(time.RFC3339Nano)
+	}
+
+	return cursor
+}
+
+// appuserCursorParams : cursor 위치를 SearchAppusers 의 keyset 조건으로 변환
+func appuserCursorParams(param *models.SearchAppusersParams, cursor *models.CursorBlock) error {
+	param.CursorID = null.IntFrom(cursor.ID)
+	param.CursorKey = null.StringFrom(cursor.Key)
+	param.CursorDir = null.StringFrom(string(cursor.Dir))
+
+	switch cursor.Key {
+	case "name":
+		param.CursorName = null.StringFrom(cursor.Value)
+	case "birthday":
+		birthday, err := time.Parse(time.DateOnly, cursor.Value)
+		if err != nil {
+			return fmt.Errorf("invalid cursor: %w", err)
+		}
+		param.CursorBirthday = null.TimeFrom(birthday)
+	case "created_at":
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return fmt.Errorf("invalid cursor: %w", err)
+		}
+		param.CursorCreatedAt = null.TimeFrom(createdAt)
+	}
+
+	return nil
 }
@@ -0,0 +1,294 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"fiber-boilerplate/internal/app/config"
+	"fiber-boilerplate/internal/defs"
+	api "fiber-boilerplate/internal/generated/serviceapi"
+	"fiber-boilerplate/internal/models"
+	"fiber-boilerplate/internal/pkg/auth"
+	logging "fiber-boilerplate/internal/pkg/logging"
+	"fiber-boilerplate/internal/pkg/revocation"
+	"fiber-boilerplate/internal/pkg/session"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Login : login / password 확인 후 새 token family 발급
+func Login(ctx *fiber.Ctx) error {
+	if config.Server.JwtSecret == "" {
+		return SendError(ctx, http.StatusNotImplemented, defs.ErrSigningDisabled)
+	}
+
+	var body api.LoginRequest
+	if err := ctx.BodyParser(&body); err != nil {
+		return SendError(ctx, http.StatusBadRequest, defs.ErrMalformedBody.Wrap(err))
+	}
+
+	credential, err := models.AppuserCredential.GetAppuserCredential(ctx.Context(), body.Login)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to get credential: %w", err))
+	}
+	// login 이 없어도 hash 비교는 수행 (응답 시간으로 login 존재 여부가 드러나지 않도록)
+	ok, err := auth.VerifyPassword(credential.PasswordHash.String, body.Password)
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to verify password: %w", err))
+	}
+	if !ok {
+		return SendError(ctx, http.StatusUnauthorized, defs.ErrInvalidCredentials)
+	}
+
+	/* Tx Begin */
+	tx, qctx, err := models.SQL.BeginxContext(ctx.Context())
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	defer tx.Rollback()
+	qtx := models.New(tx)
+	appuser, err := models.Appuser.GetAppuserByID(qtx, qctx, credential.AppuserID.Int64)
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to get appuser: %w", err))
+	}
+	if appuser.Withdraw.Bool {
+		return SendError(ctx, http.StatusForbidden, defs.ErrAppuserWithdrawn)
+	}
+
+	token, err := issueToken(qtx, qctx, appuser, uuid.NewString())
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction: %w", err))
+	}
+	/* Tx Commit */
+
+	return SendResponse(ctx, http.StatusOK, token)
+}
+
+// RefreshToken : refresh token rotation. 이미 사용된 token 이 다시 오면 탈취로 보고 family 전체 폐기
+func RefreshToken(ctx *fiber.Ctx) error {
+	if config.Server.JwtSecret == "" {
+		return SendError(ctx, http.StatusNotImplemented, defs.ErrSigningDisabled)
+	}
+
+	var body api.RefreshRequest
+	if err := ctx.BodyParser(&body); err != nil {
+		return SendError(ctx, http.StatusBadRequest, defs.ErrMalformedBody.Wrap(err))
+	}
+
+	/* Tx Begin */
+	// 같은 token 으로 동시에 요청해도 한 번만 교체되도록 row lock
+	tx, qctx, err := models.SQL.BeginxContext(ctx.Context())
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to begin transaction: %w", err))
+	}
+	defer tx.Rollback()
+	qtx := models.New(tx)
+	current, err := models.RefreshToken.GetRefreshTokenForUpdate(qtx, qctx, auth.HashRefreshToken(body.RefreshToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		return SendError(ctx, http.StatusUnauthorized, defs.ErrInvalidRefresh)
+	}
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to get refresh token: %w", err))
+	}
+
+	switch {
+	case current.RevokedAt.Valid:
+		return SendError(ctx, http.StatusUnauthorized, defs.ErrInvalidRefresh)
+
+	case current.UsedAt.Valid:
+		// 교체된 token 의 재사용: 정상 클라이언트와 탈취자 중 누가 먼저 썼는지 알 수 없으므로 모두 폐기
+		if _, err := models.RefreshToken.RevokeRefreshTokenFamily(qtx, qctx, current.Family.String); err != nil {
+			return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to revoke refresh token family: %w", err))
+		}
+		if err := tx.Commit(); err != nil {
+			return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction: %w", err))
+		}
+		logging.Warn(nil, "refresh token reused, family revoked: appuser_id=%d family=%s", current.AppuserID.Int64, current.Family.String)
+		// family (= 세션 ID) 로 이미 발급된 access token 도 폐기. 기록하지 못하면 폐기됐다고 응답하지 않음 (503)
+		if err := revocation.RevokeSession(ctx.Context(), current.Family.String); err != nil {
+			logging.Error(err, "failed to revoke session of reused refresh token: family=%s", current.Family.String)
+			return SendError(ctx, http.StatusServiceUnavailable, defs.ErrRevocationWrite.Wrap(err))
+		}
+		return SendError(ctx, http.StatusUnauthorized, defs.ErrRefreshReused)
+
+	case !current.ExpiresAt.Time.After(time.Now()):
+		return SendError(ctx, http.StatusUnauthorized, defs.ErrInvalidRefresh)
+	}
+
+	appuser, err := models.Appuser.GetAppuserByID(qtx, qctx, current.AppuserID.Int64)
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to get appuser: %w", err))
+	}
+	if appuser.Withdraw.Bool {
+		return SendError(ctx, http.StatusForbidden, defs.ErrAppuserWithdrawn)
+	}
+
+	if _, err := models.RefreshToken.UseRefreshToken(qtx, qctx, current.ID.Int64); err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to use refresh token: %w", err))
+	}
+	token, err := issueToken(qtx, qctx, appuser, current.Family.String)
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction: %w", err))
+	}
+	/* Tx Commit */
+
+	return SendResponse(ctx, http.StatusOK, token)
+}
+
+// Logout : 현재 access token 폐기 및 현재 기기의 세션 삭제. refresh token 을 함께 보내면 그 family 도 폐기.
+// access token 이 만료된 클라이언트는 refresh token 만으로 그 login 의 token 과 세션을 폐기할 수 있음
+func Logout(ctx *fiber.Ctx) error {
+	var body api.LogoutRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&body); err != nil {
+			return SendError(ctx, http.StatusBadRequest, defs.ErrMalformedBody.Wrap(err))
+		}
+	}
+
+	claims := session.Claims(ctx)
+	refreshToken := ""
+	if body.RefreshToken != nil {
+		refreshToken = *body.RefreshToken
+	}
+	if claims == nil && refreshToken == "" {
+		return SendError(ctx, http.StatusUnauthorized, defs.ErrUnauthenticated)
+	}
+
+	if refreshToken != "" {
+		var appuserID int64
+		if claims != nil {
+			data := session.FromContext(ctx)
+			if data == nil || data.Appuser == nil {
+				return SendError(ctx, http.StatusUnauthorized, defs.ErrUnauthenticated)
+			}
+			appuserID = data.Appuser.ID.Int64
+		}
+
+		/* Tx Begin */
+		tx, qctx, err := models.SQL.BeginxContext(ctx.Context())
+		if err != nil {
+			return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to begin transaction: %w", err))
+		}
+		defer tx.Rollback()
+		qtx := models.New(tx)
+		current, err := models.RefreshToken.GetRefreshTokenForUpdate(qtx, qctx, auth.HashRefreshToken(refreshToken))
+		// 다른 사용자의 token 은 폐기하지 않음
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && claims != nil && current.AppuserID.Int64 != appuserID) {
+			return SendError(ctx, http.StatusUnauthorized, defs.ErrInvalidRefresh)
+		}
+		if err != nil {
+			return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to get refresh token: %w", err))
+		}
+		if _, err := models.RefreshToken.RevokeRefreshTokenFamily(qtx, qctx, current.Family.String); err != nil {
+			return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to revoke refresh token family: %w", err))
+		}
+		appuser, err := models.Appuser.GetAppuserByID(qtx, qctx, current.AppuserID.Int64)
+		if err != nil {
+			return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to get appuser: %w", err))
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction: %w", err))
+		}
+		/* Tx Commit */
+
+		if claims == nil {
+			// access token 없이 로그아웃: family (= 세션 ID) 로 발급된 access token 과 그 기기의 세션 정리
+			if err := revocation.RevokeSession(ctx.Context(), current.Family.String); err != nil {
+				return SendError(ctx, http.StatusServiceUnavailable, defs.ErrRevocationWrite.Wrap(err))
+			}
+			if err := session.Remove(ctx, appuser.UUID.String, current.Family.String); err != nil {
+				return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to remove session: %w", err))
+			}
+			return ctx.SendStatus(http.StatusNoContent)
+		}
+	}
+
+	// 현재 access token 은 exp 까지 폐기 목록에 등록
+	if jti, _ := claims["jti"].(string); jti != "" {
+		var expiresAt time.Time
+		if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+			expiresAt = exp.Time
+		}
+		if err := revocation.RevokeToken(ctx.Context(), jti, expiresAt); err != nil {
+			return SendError(ctx, http.StatusServiceUnavailable, defs.ErrRevocationWrite.Wrap(err))
+		}
+	}
+	// 같은 login 에서 refresh 로 받은 다른 access token 과 refresh token 도 폐기 (이 기기만 로그아웃)
+	if sid, _ := claims["sid"].(string); sid != "" {
+		if err := revocation.RevokeSession(ctx.Context(), sid); err != nil {
+			return SendError(ctx, http.StatusServiceUnavailable, defs.ErrRevocationWrite.Wrap(err))
+		}
+		if _, err := models.RefreshToken.RevokeRefreshTokenFamily(nil, ctx.Context(), sid); err != nil {
+			return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to revoke refresh token family: %w", err))
+		}
+	}
+
+	if err := session.Destroy(ctx); err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to destroy session: %w", err))
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+// issueToken : access token 서명 및 family 에 새 refresh token 추가
+func issueToken(qtx *models.Queries, qctx context.Context, appuser models.AppuserBlock, family string) (*api.TokenInfo, error) {
+	now := time.Now()
+	accessTTL := time.Duration(config.Server.JWT.AccessTTL) * time.Second
+	refreshTTL := time.Duration(config.Server.JWT.RefreshTTL) * time.Second
+
+	access := auth.AccessBlock{
+		Secret:   config.Server.JwtSecret,
+		TTL:      accessTTL,
+		Issuer:   config.Server.JWT.Issuer,
+		Audience: config.Server.JWT.Audience,
+		Scopes:   config.Server.JWT.IssuedScopes,
+	}
+	// refresh token family 를 세션 ID 로 사용
+	accessToken, _, err := access.SignAccess(appuser.UUID.String, family, appuser.Role, now)
+	if errors.Is(err, auth.ErrSigningDisabled) {
+		return nil, defs.ErrSigningDisabled.Wrap(err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	refreshToken, refreshHash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	_, err = models.RefreshToken.CreateRefreshToken(qtx, qctx, models.CreateRefreshTokenParams{
+		AppuserID: appuser.ID,
+		Family:    null.StringFrom(family),
+		TokenHash: null.StringFrom(refreshHash),
+		ExpiresAt: null.TimeFrom(now.Add(refreshTTL)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return &api.TokenInfo{
+		AccessToken:      accessToken,
+		TokenType:        api.Bearer,
+		ExpiresIn:        int(accessTTL / time.Second),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int(refreshTTL / time.Second),
+	}, nil
+}
@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"net/http"
+
+	api "fiber-boilerplate/internal/generated/serviceapi"
+	"fiber-boilerplate/internal/pkg/database"
+	"fiber-boilerplate/internal/pkg/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetHealth : Redis 연결 상태. 오류 내용(주소 등)은 응답하지 않음
+func GetHealth(ctx *fiber.Ctx) error {
+	health := api.Health{
+		Status: api.HealthStatusOk,
+		Redis:  []api.RedisHealth{},
+	}
+
+	for _, redis := range database.RedisHealth() {
+		health.Redis = append(health.Redis, api.RedisHealth{
+			Db:       redis.DB,
+			State:    api.RedisHealthState(redis.State),
+			Strict:   redis.Strict,
+			Since:    util.Time.UnixMilli(redis.Since),
+			Failures: redis.Failures,
+		})
+
+		switch redis.State {
+		case database.RedisStateDown:
+			health.Status = api.HealthStatusDown
+		case database.RedisStateDegraded:
+			if health.Status == api.HealthStatusOk {
+				health.Status = api.HealthStatusDegraded
+			}
+		}
+	}
+
+	code := http.StatusOK
+	if health.Status == api.HealthStatusDown {
+		code = http.StatusServiceUnavailable
+	}
+
+	return SendResponse(ctx, code, &health)
+}
@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"fiber-boilerplate/internal/defs"
+	api "fiber-boilerplate/internal/generated/serviceapi"
+	"fiber-boilerplate/internal/models"
+	"fiber-boilerplate/internal/pkg/revocation"
+	"fiber-boilerplate/internal/pkg/session"
+	"fiber-boilerplate/internal/pkg/util"
+
+	"github.com/gofiber/fiber/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+// ListSessions : 요청한 사용자의 기기별 세션 목록
+func ListSessions(ctx *fiber.Ctx) error {
+	appuserUUID, current, err := sessionCaller(ctx)
+	if err != nil {
+		return SendError(ctx, http.StatusUnauthorized, err)
+	}
+
+	infos, err := session.List(ctx, appuserUUID)
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to list sessions: %w", err))
+	}
+
+	sessions := make([]api.Session, 0, len(infos))
+	for _, info := range infos {
+		sessions = append(sessions, sessionResponse(info, current))
+	}
+
+	return SendResponse(ctx, http.StatusOK, &api.SessionListInfo{Sessions: sessions})
+}
+
+// RevokeSession : 요청한 사용자의 세션 하나 폐기. 다른 사용자의 세션 ID 는 404
+func RevokeSession(ctx *fiber.Ctx, sessionID api.SessionIdPathParam) error {
+	appuserUUID, _, err := sessionCaller(ctx)
+	if err != nil {
+		return SendError(ctx, http.StatusUnauthorized, err)
+	}
+
+	infos, err := session.List(ctx, appuserUUID)
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to list sessions: %w", err))
+	}
+	for _, info := range infos {
+		if info.ID != sessionID {
+			continue
+		}
+		if err := revokeSession(ctx, info); err != nil {
+			return SendError(ctx, http.StatusInternalServerError, err)
+		}
+		return ctx.SendStatus(http.StatusNoContent)
+	}
+
+	return SendError(ctx, http.StatusNotFound, defs.ErrRecordNotFound.WithMessage("session not found: %s", sessionID))
+}
+
+// RevokeOtherSessions : 현재 세션을 제외하고 모두 폐기
+func RevokeOtherSessions(ctx *fiber.Ctx) error {
+	appuserUUID, current, err := sessionCaller(ctx)
+	if err != nil {
+		return SendError(ctx, http.StatusUnauthorized, err)
+	}
+
+	infos, err := session.List(ctx, appuserUUID)
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to list sessions: %w", err))
+	}
+	for _, info := range infos {
+		if info.ID == current {
+			continue
+		}
+		if err := revokeSession(ctx, info); err != nil {
+			return SendError(ctx, http.StatusInternalServerError, err)
+		}
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+// sessionCaller : 요청한 사용자의 uuid 와 현재 세션 ID
+func sessionCaller(ctx *fiber.Ctx) (appuserUUID string, current string, err error) {
+	claims := session.Claims(ctx)
+	appuserUUID, _ = claims["uuid"].(string)
+	if appuserUUID == "" {
+		return "", "", defs.ErrUnauthenticated
+	}
+
+	current, _ = session.ID(claims)
+	return appuserUUID, current, nil
+}
+
+// revokeSession : 세션을 지운 뒤에도 남은 토큰으로 다시 만들어지지 않도록 토큰을 먼저 폐기.
+// sid 세션은 sid 와 refresh token family, jti 세션은 그 access token 을 폐기
+func revokeSession(ctx *fiber.Ctx, info session.InfoBlock) error {
+	switch info.Kind {
+	case session.KindSID:
+		if err := revocation.RevokeSession(ctx.Context(), info.ID); err != nil {
+			return fmt.Errorf("failed to revoke session: %w", err)
+		}
+		if _, err := models.RefreshToken.RevokeRefreshTokenFamily(nil, ctx.Context(), info.ID); err != nil {
+			return fmt.Errorf("failed to revoke refresh token family: %w", err)
+		}
+	case session.KindJTI:
+		if err := revocation.RevokeToken(ctx.Context(), info.ID, info.TokenExpiresAt); err != nil {
+			return fmt.Errorf("failed to revoke access token: %w", err)
+		}
+	}
+
+	if err := session.Remove(ctx, info.AppuserUUID, info.ID); err != nil {
+		return fmt.Errorf("failed to remove session: %w", err)
+	}
+
+	return nil
+}
+
+// sessionResponse :
+func sessionResponse(info session.InfoBlock, current string) api.Session {
+	resp := api.Session{
+		Id:         info.ID,
+		Current:    info.ID == current,
+		CreatedAt:  util.Time.UnixMilli(info.CreatedAt),
+		LastSeenAt: util.Time.UnixMilli(info.LastSeenAt),
+		ExpiresAt:  models.NullableTS(null.NewTime(info.ExpiresAt, !info.ExpiresAt.IsZero())),
+	}
+	if info.Device != "" {
+		resp.Device = &info.Device
+	}
+	if info.IP != "" {
+		resp.Ip = &info.IP
+	}
+
+	return resp
+}
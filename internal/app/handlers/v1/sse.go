@@ -2,128 +2,132 @@ package v1
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
+	"fiber-boilerplate/internal/app/config"
 	"fiber-boilerplate/internal/defs"
+	api "fiber-boilerplate/internal/generated/serviceapi"
+	"fiber-boilerplate/internal/pkg/authz"
 	"fiber-boilerplate/internal/pkg/logging"
+	"fiber-boilerplate/internal/pkg/realtime"
+	"fiber-boilerplate/internal/pkg/revocation"
 	"fiber-boilerplate/internal/pkg/session"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// getRedisStore Redis Store 조회
-func getRedisStore(ctx *fiber.Ctx) (*session.StoreBlock, error) {
-	if store := ctx.Locals(defs.KeyStore); store != nil {
-		if redisCtx, ok := store.(*session.StoreBlock); ok {
-			return redisCtx, nil
-		}
-	}
+const (
+	// sseHeartbeat : proxy 가 유휴 연결을 끊지 않도록 보내는 comment 간격. write 실패로 끊긴 클라이언트도 알 수 있음
+	sseHeartbeat = 15 * time.Second
+	// sseRetry : 끊겼을 때 EventSource 가 다시 연결할 때까지 기다리는 시간 (ms)
+	sseRetry = 3000
+)
 
-	redisCtx := session.GetStore(defs.KeyStore)
-	if redisCtx == nil {
-		return nil, defs.ErrInvalid
+// SseOpen : topic 을 구독하고 클라이언트가 끊거나, 토큰이 만료 또는 폐기되거나, 서버가 종료될 때까지 이벤트 전송.
+// Last-Event-ID 가 있으면 (EventSource 가 다시 연결할 때 보냄) 그 뒤에 발행된 이벤트를 먼저 보냄
+func SseOpen(ctx *fiber.Ctx, params api.SseOpenParams) error {
+	if err := authorizeTopics(ctx, params.Topic...); err != nil {
+		return SendError(ctx, http.StatusForbidden, err)
 	}
 
-	return redisCtx, nil
-}
-
-func SseOpen(ctx *fiber.Ctx) error {
-	// HTTP 헤더 설정
-	ctx.Set("Content-Type", "text/event-stream")
-	ctx.Set("Cache-Control", "no-cache")
-	ctx.Set("Connection", "keep-alive")
-	ctx.Set("Transfer-Encoding", "chunked")
-
-	// Redis Store 초기화
-	redisCtx, err := getRedisStore(ctx)
+	var (
+		sub    *realtime.SubscriberBlock
+		missed []realtime.EventBlock
+		err    error
+	)
+	lastEventID := ctx.Get("Last-Event-ID")
+	if lastEventID == "" && params.LastEventId != nil {
+		lastEventID = *params.LastEventId
+	}
+	if lastEventID != "" {
+		sub, missed, err = realtime.Resume(context.WithoutCancel(ctx.Context()), lastEventID, params.Topic...)
+	} else {
+		sub, err = realtime.Subscribe(params.Topic...)
+	}
+	if errors.Is(err, realtime.ErrInvalidTopic) {
+		return SendError(ctx, http.StatusBadRequest, defs.ErrInvalidParameter.WithMessage("invalid topic"))
+	}
+	if errors.Is(err, realtime.ErrInvalidEventID) {
+		return SendError(ctx, http.StatusBadRequest, defs.ErrInvalidParameter.WithMessage("invalid Last-Event-ID"))
+	}
 	if err != nil {
-		return SendError(ctx, http.StatusInternalServerError, err)
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to subscribe: %w", err))
 	}
 
-	// 메시지 채널과 context 미리 저장
-	messageChan := make(chan string, 1)
-	done := make(chan struct{})
+	// stream 은 handler 가 반환된 뒤에 쓰므로 클레임을 미리 복사
+	claims := session.Claims(ctx)
 
-	requestCtx := ctx.Context()
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set(fiber.HeaderConnection, "keep-alive")
+	// nginx 가 응답을 모아 보내지 않도록
+	ctx.Set("X-Accel-Buffering", "no")
 
-	// Redis Subscribe를 별도 goroutine에서 실행
-	go func() {
-		defer close(done)
+	ctx.Status(http.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
 
-		goerr := redisCtx.SubscribeChannel(requestCtx, defs.KeyStore)
+		ticker := time.NewTicker(sseHeartbeat)
+		defer ticker.Stop()
 
-		// context가 이미 cancel되었으면 보내지 않음
-		select {
-		case <-requestCtx.Done():
-			return
-		default:
+		var expired <-chan time.Time
+		if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+			timer := time.NewTimer(time.Until(exp.Time))
+			defer timer.Stop()
+			expired = timer.C
 		}
 
-		var msg string
-		switch {
-		case goerr == nil:
-			msg = "End"
-		case errors.Is(goerr, defs.ErrTimeout):
-			msg = "Timeout"
-		default:
-			logging.Error(goerr, "Redis subscribe error")
-			msg = fmt.Sprintf("Error: %s", goerr.Error())
+		if err := writeSSE(w, "retry: %d\n\n", sseRetry); err != nil {
+			return
 		}
-
-		// 무한 블로킹을 피하기 위한 논블로킹 전송
-		select {
-		case messageChan <- msg:
-			logging.Trace("Sent message to channel: %s", msg)
-		case <-requestCtx.Done():
-			logging.Trace("Request context already done, skipping message send")
-		default:
-			logging.Trace("Message channel full or receiver closed, skipping send")
+		for _, event := range missed {
+			if err := writeEvent(w, event); err != nil {
+				logging.Trace("SSE client disconnected: %v", err)
+				return
+			}
 		}
-	}()
 
-	// SSE 스트리밍
-	ctx.Status(http.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
-		ticker := time.NewTicker(1 * time.Second)
-		defer ticker.Stop()
-
-		var i int = 1
 		for {
 			select {
-			case <-requestCtx.Done():
-				logging.Trace("Client connection closed")
+			case <-sub.Done():
+				logging.Trace("SSE subscription closed: %v", sub.Topics())
 				return
-			case msg := <-messageChan:
-				// Redis 메시지 수신
-				logging.Trace("Received Redis message: %s", msg)
-				message := fmt.Sprintf("data: %s\n\n", msg)
-				if _, err := w.Write([]byte(message)); err != nil {
-					logging.Trace("Write error: %v", err)
+
+			case event := <-sub.Events():
+				if sub.Replayed(event) {
+					continue
+				}
+				if err := writeEvent(w, event); err != nil {
+					logging.Trace("SSE client disconnected: %v", err)
 					return
 				}
-				if err := w.Flush(); err != nil {
-					logging.Trace("Flush error: %v", err)
+				if event.Name == realtime.EventClose {
 					return
 				}
-				return // Redis 메시지 수신 후 종료
-			case <-done:
-				// Goroutine이 종료됨 (메시지를 보낼 수 없는 경우)
-				logging.Trace("Subscribe goroutine completed")
+
+			case <-expired:
+				_ = writeClose(w, "token expired")
 				return
+
 			case <-ticker.C:
-				// 주기적으로 heartbeat 전송
-				message := fmt.Sprintf("data: %d sec\n\n", i)
-				if _, err := w.Write([]byte(message)); err != nil {
-					logging.Trace("Write error: %v", err)
+				// 연결 중에 폐기된 토큰 (logout, 사용자 단위 폐기) 이면 종료. 확인하지 못하면 다음 heartbeat 에 다시 확인
+				err := revocation.CheckClaims(context.Background(), claims)
+				if errors.Is(err, revocation.ErrRevoked) {
+					_ = writeClose(w, "token revoked")
 					return
 				}
-				if err := w.Flush(); err != nil {
-					logging.Trace("Flush error: %v", err)
+				if err != nil {
+					logging.Warn(err, "Failed to check SSE token revocation")
+				}
+
+				if err := writeSSE(w, ": ping\n\n"); err != nil {
+					logging.Trace("SSE client disconnected: %v", err)
 					return
 				}
-				i++
 			}
 		}
 	})
@@ -131,20 +135,65 @@ func SseOpen(ctx *fiber.Ctx) error {
 	return nil
 }
 
-func SseClose(ctx *fiber.Ctx) error {
-	// Redis Store 초기화
-	redisCtx, err := getRedisStore(ctx)
+// IssueSseTicket : EventSource 로 SseOpen 을 열 때 query 로 보낼 ticket
+func IssueSseTicket(ctx *fiber.Ctx) error {
+	ttl := time.Duration(config.Server.SSE.TicketTTL) * time.Second
+	ticket, expiresAt, err := realtime.IssueTicket(context.WithoutCancel(ctx.Context()), session.Claims(ctx), ttl)
 	if err != nil {
-		return SendError(ctx, http.StatusInternalServerError, err)
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to issue ticket: %w", err))
 	}
 
-	// Publish message to channel
-	requestCtx := ctx.Context()
-	err = redisCtx.PublishChannel(requestCtx, defs.KeyStore, "Hello World")
+	return SendResponse(ctx, http.StatusOK, api.SseTicket{
+		Ticket:    ticket,
+		ExpiresAt: expiresAt.UnixMilli(),
+	})
+}
+
+// PublishEvent : 내부 서비스가 topic 의 구독자에게 이벤트 발행. close 이벤트는 구독자의 stream 을 닫음
+func PublishEvent(ctx *fiber.Ctx) error {
+	var body api.PublishEventRequest
+	if err := ctx.BodyParser(&body); err != nil {
+		return SendError(ctx, http.StatusBadRequest, defs.ErrMalformedBody.Wrap(err))
+	}
+
+	err := realtime.Publish(context.WithoutCancel(ctx.Context()), body.Topic, body.Event, body.Data)
+	if errors.Is(err, realtime.ErrInvalidTopic) {
+		return SendError(ctx, http.StatusBadRequest, defs.ErrInvalidParameter.WithMessage("invalid topic: %s", body.Topic))
+	}
+	if errors.Is(err, realtime.ErrInvalidEvent) {
+		return SendError(ctx, http.StatusBadRequest, defs.ErrInvalidParameter.WithMessage("invalid event: %s", body.Event))
+	}
 	if err != nil {
-		logging.Error(err, "Failed to publish SSE close message")
-		return SendError(ctx, http.StatusInternalServerError, err)
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to publish event: %w", err))
 	}
 
 	return SendGeneric(ctx, http.StatusOK)
 }
+
+// authorizeTopics : 토큰의 사용자가 구독할 수 있는 topic 인지 (SSE, WebSocket)
+func authorizeTopics(ctx *fiber.Ctx, topics ...string) error {
+	appuserUUID, _ := session.Claims(ctx)["uuid"].(string)
+	return realtime.AuthorizeTopics(authz.FromContext(ctx), appuserUUID, topics...)
+}
+
+// writeEvent : ID 가 없는 이벤트 (close) 는 클라이언트의 Last-Event-ID 를 바꾸지 않도록 id 를 보내지 않음
+func writeEvent(w *bufio.Writer, event realtime.EventBlock) error {
+	if event.ID == "" {
+		return writeSSE(w, "event: %s\ndata: %s\n\n", event.Name, event.Data)
+	}
+	return writeSSE(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Name, event.Data)
+}
+
+// writeClose : 서버가 stream 을 닫는 이유를 close 이벤트로 전송
+func writeClose(w *bufio.Writer, reason string) error {
+	data, _ := json.Marshal(map[string]string{"reason": reason})
+	return writeEvent(w, realtime.EventBlock{Name: realtime.EventClose, Data: data})
+}
+
+// writeSSE : frame 마다 flush
+func writeSSE(w *bufio.Writer, format string, values ...interface{}) error {
+	if _, err := fmt.Fprintf(w, format, values...); err != nil {
+		return err
+	}
+	return w.Flush()
+}
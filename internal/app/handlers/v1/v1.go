@@ -4,10 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"fiber-boilerplate/internal/defs"
 	api "fiber-boilerplate/internal/generated/serviceapi"
 	"fiber-boilerplate/internal/models"
+	"fiber-boilerplate/internal/pkg/database"
 	logging "fiber-boilerplate/internal/pkg/logging"
 	"fiber-boilerplate/internal/pkg/util"
 
@@ -16,10 +18,17 @@ import (
 )
 
 const (
+	// ProblemContentType : RFC 9457 Problem Details
+	ProblemContentType = "application/problem+json"
+	// ProblemTypeDefault : code 로 구분하므로 type 은 별도 URI 없이 about:blank
+	ProblemTypeDefault = "about:blank"
+
 	// MaxPaginationLimit is the maximum number of items that can be requested per page
 	MaxPaginationLimit = 1000
 	// MaxPaginationOffset is the maximum offset allowed for pagination
 	MaxPaginationOffset = 1000000
+	// DefaultPaginationLimit is the number of items per page when limit is omitted in cursor mode
+	DefaultPaginationLimit = 10
 )
 
 // EntityResponse :
@@ -43,6 +52,31 @@ func EntityResponse(entity interface{}) (response api.EntityResponse) {
 	return
 }
 
+// EntityETag : version 컬럼 기반 entity ETag
+func EntityETag(version null.Int) string {
+	return strconv.Quote(strconv.FormatInt(version.Int64, 10))
+}
+
+// IfMatchVersion : If-Match 의 entity ETag 를 version 으로 변환. 헤더가 없거나 * 이면 Valid 가 false
+func IfMatchVersion(ctx *fiber.Ctx) (null.Int, error) {
+	ifMatch := ctx.Get(fiber.HeaderIfMatch)
+	if ifMatch == "" || ifMatch == "*" {
+		return null.Int{}, nil
+	}
+
+	// weak ETag 는 body hash 이므로 version 으로 쓸 수 없음
+	raw, err := strconv.Unquote(ifMatch)
+	if err != nil {
+		return null.Int{}, defs.ErrInvalidParameter.WithMessage("invalid If-Match: %q", ifMatch)
+	}
+	version, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return null.Int{}, defs.ErrInvalidParameter.WithMessage("invalid If-Match: %q", ifMatch)
+	}
+
+	return null.IntFrom(version), nil
+}
+
 // SendGeneric :
 func SendGeneric(ctx *fiber.Ctx, code int, messageOpt ...string) error {
 	response := &api.GenericResponse{
@@ -72,63 +106,102 @@ func SendResponse(ctx *fiber.Ctx, code int, data interface{}) error {
 }
 
 // SendError :
+// errs[0] 이 defs.AppError 면 그 status / code / message 로, 아니면 code 의 기본 AppError 로 응답한다.
+// 원인 error 는 서버 로그에만 남기고 응답에는 넣지 않는다
 func SendError(ctx *fiber.Ctx, code int, errs ...error) error {
-	response := &api.GenericResponse{
-		Code: code,
+	var err error
+	if len(errs) > 0 {
+		err = errs[0]
 	}
 
-	var err error
-	if len(errs) == 0 || errs[0] == nil {
-		err = defs.NewError(code)
+	return SendErrorData(ctx, code, err, nil)
+}
+
+// SendErrorData : problem 의 data 에 추가 정보(예. 409 의 현재 엔티티)를 담아 응답
+func SendErrorData(ctx *fiber.Ctx, code int, err error, data interface{}) error {
+	appErr := toAppError(code, err)
+	if appErr.Status >= http.StatusInternalServerError {
+		logging.Error(err, "%s %s", ctx.Method(), ctx.Path())
 	} else {
-		err = errs[0]
+		logging.Warn(err, "%s %s", ctx.Method(), ctx.Path())
+	}
+
+	detail := appErr.Message
+	instance := ctx.Path()
+	response := &api.Problem{
+		Type:     ProblemTypeDefault,
+		Title:    http.StatusText(appErr.Status),
+		Status:   appErr.Status,
+		Code:     appErr.Code,
+		Detail:   &detail,
+		Instance: &instance,
+	}
+	if len(appErr.Violations) > 0 {
+		violations := make([]api.Violation, 0, len(appErr.Violations))
+		for _, v := range appErr.Violations {
+			violations = append(violations, api.Violation{Field: v.Field, Message: v.Message})
+		}
+		response.Violations = &violations
+	}
+	if data != nil {
+		response.Data = &data
 	}
 
+	return ctx.Status(appErr.Status).JSON(response, ProblemContentType)
+}
+
+// toAppError :
+func toAppError(code int, err error) *defs.AppError {
+	// DB error (sql.ErrNoRows, SQLSTATE) 는 code 와 상관없이 정해진 status 로 응답
+	err = database.TranslateError(err)
+
+	var appErr *defs.AppError
 	var fiberError *fiber.Error
 	switch {
+	case errors.As(err, &appErr):
+		return appErr
 	case errors.As(err, &fiberError):
-		var e *fiber.Error
-		errors.As(err, &e)
-		response.Message = e.Message
-		logging.Warn(defs.NewError(e.Code), "%+v", err)
+		appErr = defs.StatusAppError(fiberError.Code).Wrap(err)
+		// fiber 가 만든 4xx message 는 내부 정보를 포함하지 않음
+		if fiberError.Code < http.StatusInternalServerError {
+			appErr.Message = fiberError.Message
+		}
+		return appErr
+	case err == nil:
+		return defs.StatusAppError(code)
 	default:
-		response.Message = err.Error()
-		logging.Warn(err, "")
+		return defs.StatusAppError(code).Wrap(err)
 	}
-
-	// Use the actual error code instead of StatusTeapot
-	return ctx.Status(code).JSON(response)
 }
 
 // EntityListParam :
-func EntityListParam(sortingParam *api.SortingQueryParam, paginationParam *api.PaginationQueryParam) (*models.SortingBlock, *models.PaginationBlock, error) {
-	var sorting models.SortingBlock
+// columns 는 쿼리별로 정렬을 허용하는 컬럼 목록이며, 목록에 없는 key 는 에러로 처리한다
+func EntityListParam(sortingParam *api.SortingQueryParam, paginationParam *api.PaginationQueryParam, columns models.SortColumns) (*models.SortingBlock, *models.PaginationBlock, error) {
+	var sorting *models.SortingBlock
 	var pagination models.PaginationBlock
 
 	if sortingParam != nil {
 		var keys []string
 		if sortingParam.Keys != nil {
-			for _, key := range *sortingParam.Keys {
-				keys = append(keys, key)
-			}
+			keys = *sortingParam.Keys
 		}
 
 		var dirs []string
 		if sortingParam.Dirs != nil {
-			for _, dir := range *sortingParam.Dirs {
-				dirs = append(dirs, dir)
-			}
+			dirs = *sortingParam.Dirs
 		}
 
-		if len(keys) > 0 && len(dirs) > 0 {
-			if len(keys) != len(dirs) {
-				return nil, nil, fmt.Errorf("sorting parameter mismatch: keys=%d, dirs=%d", len(keys), len(dirs))
-			}
-			sorting.Provided = true
-			for i := range keys {
-				sorting.Orders = append(sorting.Orders, util.String.Words(keys[i], dirs[i]))
-			}
+		var err error
+		sorting, err = columns.Sorting(keys, dirs)
+		if err != nil {
+			return nil, nil, err
 		}
+	} else {
+		sorting = new(models.SortingBlock)
+	}
+
+	if paginationParam != nil && paginationParam.Cursor != nil {
+		return entityCursorParam(sorting, paginationParam, columns)
 	}
 
 	if paginationParam != nil {
@@ -156,5 +229,68 @@ func EntityListParam(sortingParam *api.SortingQueryParam, paginationParam *api.P
 		}
 	}
 
-	return &sorting, &pagination, nil
+	return sorting, &pagination, nil
+}
+
+// entityCursorParam : cursor(keyset) 페이징
+// 단일 정렬 key 와 id 로 정렬하며, 두 번째 페이지부터는 cursor 의 정렬과 요청 정렬이 같아야 한다
+func entityCursorParam(sorting *models.SortingBlock, paginationParam *api.PaginationQueryParam, columns models.SortColumns) (*models.SortingBlock, *models.PaginationBlock, error) {
+	if paginationParam.Page != nil {
+		return nil, nil, fmt.Errorf("pagination cursor and page cannot be used together")
+	}
+
+	limit := DefaultPaginationLimit
+	if paginationParam.Limit != nil {
+		limit = *paginationParam.Limit
+	}
+	if limit <= 0 {
+		return nil, nil, fmt.Errorf("invalid pagination parameters: limit=%d (must be positive)", limit)
+	}
+	if limit > MaxPaginationLimit {
+		return nil, nil, fmt.Errorf("pagination limit too large: %d (max: %d)", limit, MaxPaginationLimit)
+	}
+
+	cursor, err := columns.DecodeCursor(*paginationParam.Cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(sorting.Orders) > 1 {
+		return nil, nil, fmt.Errorf("cursor pagination supports a single sorting key: keys=%d", len(sorting.Orders))
+	}
+
+	dir := models.SortAsc
+	if sorting.Provided {
+		dir = sorting.Orders[0].Dir
+	}
+
+	if cursor != nil {
+		switch {
+		case sorting.Provided:
+			if sorting.Orders[0].Key != cursor.Key || sorting.Orders[0].Dir != cursor.Dir {
+				return nil, nil, fmt.Errorf("pagination cursor does not match sorting parameters")
+			}
+		case cursor.Key != models.CursorKeyID:
+			sorting, err = columns.Sorting([]string{cursor.Key}, []string{string(cursor.Dir)})
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		dir = cursor.Dir
+	}
+
+	// 같은 값이 여러 row 에 있어도 순서가 고정되도록 id 를 마지막 정렬 기준으로 추가
+	sorting.Provided = true
+	sorting.Orders = append(sorting.Orders, models.OrderBlock{
+		Key:    models.CursorKeyID,
+		Column: "id",
+		Dir:    dir,
+	})
+
+	return sorting, &models.PaginationBlock{
+		Provided: true,
+		Limit:    limit,
+		Keyset:   true,
+		Cursor:   cursor,
+	}, nil
 }
@@ -0,0 +1,250 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"fiber-boilerplate/internal/defs"
+	api "fiber-boilerplate/internal/generated/serviceapi"
+	"fiber-boilerplate/internal/pkg/authz"
+	"fiber-boilerplate/internal/pkg/logging"
+	"fiber-boilerplate/internal/pkg/realtime"
+	"fiber-boilerplate/internal/pkg/session"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// wsPingInterval : ping 간격. wsPongWait 동안 pong 이나 메시지를 받지 못하면 끊긴 것으로 봄
+	wsPingInterval = 30 * time.Second
+	wsPongWait     = 60 * time.Second
+	// wsWriteWait : frame 하나를 보내는 제한 시간. 클라이언트가 읽지 않아 넘기면 연결을 닫음
+	wsWriteWait = 10 * time.Second
+	// wsCloseWait : close frame 을 보낸 뒤 클라이언트의 close 를 기다리는 시간
+	wsCloseWait = time.Second
+	// wsMaxMessage : 클라이언트 메시지 최대 크기 (byte)
+	wsMaxMessage = 64 * 1024
+	// wsReplyBuffer : 보내지 못한 reply 수. 가득 차면 다음 메시지를 읽지 않음
+	wsReplyBuffer = 16
+
+	wsSubscriberKey = "fiber-boilerplate/#/wsSubscriber"
+	wsClientKey     = "fiber-boilerplate/#/wsClient"
+)
+
+// frame type
+const (
+	wsTypeEvent = "event"
+	wsTypeReply = "reply"
+	wsTypeError = "error"
+)
+
+// wsMessageBlock : 주고받는 JSON frame. type 에 따라 쓰는 field 가 다름
+type wsMessageBlock struct {
+	Type string `json:"type"`
+	Ref  string `json:"ref,omitempty"`
+	// event
+	ID    string `json:"id,omitempty"`
+	Topic string `json:"topic,omitempty"`
+	Event string `json:"event,omitempty"`
+	// event, reply, 클라이언트 메시지
+	Data json.RawMessage `json:"data,omitempty"`
+	// error
+	Status  int    `json:"status,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// wsUpgrade : 구독과 인증 정보는 Locals 로 넘김
+var wsUpgrade = websocket.New(serveWS)
+
+// WsOpen : topic 을 구독하는 WebSocket. 이벤트를 보내고 클라이언트 메시지는 realtime.HandleMessage 로 등록된 handler 로 처리
+func WsOpen(ctx *fiber.Ctx, params api.WsOpenParams) error {
+	if !websocket.IsWebSocketUpgrade(ctx) {
+		return SendError(ctx, http.StatusUpgradeRequired, fiber.ErrUpgradeRequired)
+	}
+	if err := authorizeTopics(ctx, params.Topic...); err != nil {
+		return SendError(ctx, http.StatusForbidden, err)
+	}
+
+	sub, err := realtime.Subscribe(params.Topic...)
+	if errors.Is(err, realtime.ErrInvalidTopic) {
+		return SendError(ctx, http.StatusBadRequest, defs.ErrInvalidParameter.WithMessage("invalid topic"))
+	}
+	if err != nil {
+		return SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to subscribe: %w", err))
+	}
+
+	claims := session.Claims(ctx)
+	appuserUUID, _ := claims["uuid"].(string)
+	ctx.Locals(wsSubscriberKey, sub)
+	ctx.Locals(wsClientKey, &realtime.ClientBlock{
+		AppuserUUID: appuserUUID,
+		Claims:      claims,
+		Permission:  authz.FromContext(ctx),
+		Subscriber:  sub,
+	})
+
+	if err := wsUpgrade(ctx); err != nil {
+		sub.Close()
+		return SendError(ctx, http.StatusUpgradeRequired, err)
+	}
+
+	return nil
+}
+
+// serveWS : 보내기는 이 goroutine 에서만, 읽기는 readWS 에서.
+// 클라이언트가 닫거나, 토큰이 만료되거나, 서버가 종료될 때까지 (hub 가 구독을 닫음)
+func serveWS(conn *websocket.Conn) {
+	sub := conn.Locals(wsSubscriberKey).(*realtime.SubscriberBlock)
+	client := conn.Locals(wsClientKey).(*realtime.ClientBlock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	replies := make(chan wsMessageBlock, wsReplyBuffer)
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		readWS(ctx, conn, client, replies)
+	}()
+	// conn 은 반환 후 재사용되므로 읽기가 끝날 때까지 기다림
+	defer func() {
+		cancel()
+		_ = conn.Close()
+		<-readDone
+		sub.Close()
+	}()
+
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	var expired <-chan time.Time
+	if exp, err := client.Claims.GetExpirationTime(); err == nil && exp != nil {
+		timer := time.NewTimer(time.Until(exp.Time))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for {
+		var err error
+		select {
+		case <-readDone:
+			return
+
+		case <-sub.Done():
+			closeWS(conn, readDone, websocket.CloseGoingAway, "server shutting down")
+			return
+
+		case <-expired:
+			closeWS(conn, readDone, websocket.ClosePolicyViolation, "token expired")
+			return
+
+		case event := <-sub.Events():
+			err = writeWS(conn, wsMessageBlock{
+				Type:  wsTypeEvent,
+				ID:    event.ID,
+				Topic: event.Topic,
+				Event: event.Name,
+				Data:  event.Data,
+			})
+			if err == nil && event.Name == realtime.EventClose {
+				closeWS(conn, readDone, websocket.CloseNormalClosure, "closed by server")
+				return
+			}
+
+		case reply := <-replies:
+			err = writeWS(conn, reply)
+
+		case <-ticker.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait))
+		}
+
+		if err != nil {
+			logging.Trace("WebSocket client disconnected: %v", err)
+			return
+		}
+	}
+}
+
+// readWS : 메시지를 하나씩 처리. reply 를 보내지 못하고 쌓이면 읽기를 멈춤
+func readWS(ctx context.Context, conn *websocket.Conn, client *realtime.ClientBlock, replies chan<- wsMessageBlock) {
+	conn.SetReadLimit(wsMaxMessage)
+	_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logging.Trace("WebSocket read failed: %v", err)
+			}
+			return
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+		select {
+		case replies <- handleWS(ctx, client, raw):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handleWS : 클라이언트 메시지 하나의 reply 또는 error
+func handleWS(ctx context.Context, client *realtime.ClientBlock, raw []byte) wsMessageBlock {
+	var msg wsMessageBlock
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return wsError("", defs.ErrMalformedBody.Wrap(err))
+	}
+	if msg.Type == "" {
+		return wsError(msg.Ref, defs.ErrMalformedBody.WithMessage("message type is required"))
+	}
+
+	data, err := realtime.DispatchMessage(ctx, client, msg.Type, msg.Data)
+	if err != nil {
+		return wsError(msg.Ref, err)
+	}
+	reply, err := json.Marshal(data)
+	if err != nil {
+		return wsError(msg.Ref, err)
+	}
+
+	return wsMessageBlock{Type: wsTypeReply, Ref: msg.Ref, Data: reply}
+}
+
+// wsError : problem 응답과 같은 status, code, message
+func wsError(ref string, err error) wsMessageBlock {
+	appErr := toAppError(http.StatusInternalServerError, err)
+	if appErr.Status >= http.StatusInternalServerError {
+		logging.Error(err, "WebSocket message failed")
+	} else {
+		logging.Warn(err, "WebSocket message rejected")
+	}
+
+	return wsMessageBlock{
+		Type:    wsTypeError,
+		Ref:     ref,
+		Status:  appErr.Status,
+		Code:    appErr.Code,
+		Message: appErr.Message,
+	}
+}
+
+func writeWS(conn *websocket.Conn, msg wsMessageBlock) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return conn.WriteJSON(msg)
+}
+
+// closeWS : close frame 을 보내고 클라이언트의 close 를 잠시 기다림
+func closeWS(conn *websocket.Conn, readDone <-chan struct{}, code int, text string) {
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(wsWriteWait))
+	select {
+	case <-readDone:
+	case <-time.After(wsCloseWait):
+	}
+}
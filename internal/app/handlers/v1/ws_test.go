@@ -0,0 +1,127 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	api "fiber-boilerplate/internal/generated/serviceapi"
+	"fiber-boilerplate/internal/pkg/realtime"
+	"fiber-boilerplate/internal/pkg/session"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// dialWS : appuser u1 (admin 아님) 로 user:u1 을 구독한 연결
+func dialWS(t *testing.T) *websocket.Conn {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	// Redis 가 없으면 in-memory fallback
+	realtime.Setup(ctx)
+
+	store := &session.StoreBlock{KeyName: "test/claims"}
+	app := fiber.New()
+	app.Get("/ws", func(c *fiber.Ctx) error {
+		c.Locals(session.ContextKeyStore, store)
+		c.Locals(store.KeyName, jwt.MapClaims{"uuid": "u1"})
+		return WsOpen(c, api.WsOpenParams{Topic: []string{"user:u1"}})
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = app.Listener(ln) }()
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+ln.Addr().String()+"/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func readFrame(t *testing.T, conn *websocket.Conn) wsMessageBlock {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var msg wsMessageBlock
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestWsMessage(t *testing.T) {
+	conn := dialWS(t)
+
+	tests := []struct {
+		name   string
+		send   string
+		typ    string
+		code   string
+		topics []string
+	}{
+		{"unsubscribe", `{"type":"unsubscribe","ref":"1","data":{"topics":["user:u1"]}}`, wsTypeReply, "", []string{}},
+		{"subscribe other user", `{"type":"subscribe","ref":"2","data":{"topics":["user:u2"]}}`, wsTypeError, "insufficient_permission", nil},
+		{"subscribe without topics", `{"type":"subscribe","ref":"3","data":{}}`, wsTypeError, "invalid_parameter", nil},
+		{"subscribe", `{"type":"subscribe","ref":"4","data":{"topics":["user:u1"]}}`, wsTypeReply, "", []string{"user:u1"}},
+		{"subscribe again", `{"type":"subscribe","ref":"5","data":{"topics":["user:u1"]}}`, wsTypeReply, "", []string{"user:u1"}},
+		{"unknown", `{"type":"typing","ref":"6"}`, wsTypeError, "unknown_message", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(tt.send)); err != nil {
+				t.Fatal(err)
+			}
+
+			var sent wsMessageBlock
+			_ = json.Unmarshal([]byte(tt.send), &sent)
+			msg := readFrame(t, conn)
+			if msg.Type != tt.typ || msg.Ref != sent.Ref || msg.Code != tt.code {
+				t.Fatalf("reply = %+v, want type %s, ref %s, code %q", msg, tt.typ, sent.Ref, tt.code)
+			}
+			if tt.typ != wsTypeReply {
+				return
+			}
+
+			var reply struct {
+				Topics []string `json:"topics"`
+			}
+			if err := json.Unmarshal(msg.Data, &reply); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(reply.Topics, tt.topics) {
+				t.Errorf("topics = %v, want %v", reply.Topics, tt.topics)
+			}
+		})
+	}
+
+	// 다시 구독한 topic 의 이벤트를 받음. hub 가 채널을 구독하기 전에 발행한 이벤트는 사라지므로 받을 때까지 발행
+	received := make(chan struct{})
+	defer close(received)
+	go func() {
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			_ = realtime.Publish(context.Background(), "user:u1", "notice", map[string]string{"hello": "world"})
+			select {
+			case <-received:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	msg := readFrame(t, conn)
+	if msg.Type != wsTypeEvent || msg.Topic != "user:u1" || msg.Event != "notice" {
+		t.Errorf("event = %+v", msg)
+	}
+}
@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"fiber-boilerplate/internal/app/config"
+	"fiber-boilerplate/internal/models"
+	"fiber-boilerplate/internal/pkg/logging"
+)
+
+// Start : 주기 작업 시작. ctx 가 취소되면 모든 작업 종료
+func Start(ctx context.Context) {
+	withdraw := config.Server.Withdraw
+	if withdraw.RetentionDays > 0 && withdraw.AnonymizeInterval > 0 {
+		go run(ctx, "anonymize withdrawn appusers", time.Duration(withdraw.AnonymizeInterval)*time.Second, anonymizeWithdrawnAppusers)
+	}
+	if interval := config.Server.JWT.RefreshPurgeInterval; interval > 0 {
+		go run(ctx, "purge expired refresh tokens", time.Duration(interval)*time.Second, purgeExpiredRefreshTokens)
+	}
+}
+
+// run : 시작 시 한 번 실행 후 interval 마다 반복
+func run(ctx context.Context, name string, interval time.Duration, job func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job(ctx); err != nil {
+			logging.Warn(err, "job failed: %s", name)
+		}
+
+		select {
+		case <-ctx.Done():
+			logging.Trace("job stopped: %s", name)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// anonymizeWithdrawnAppusers : 보관 기간이 지난 탈퇴 사용자의 개인정보 익명화
+func anonymizeWithdrawnAppusers(ctx context.Context) error {
+	before := time.Now().AddDate(0, 0, -config.Server.Withdraw.RetentionDays)
+
+	uuids, err := models.Appuser.AnonymizeWithdrawnAppusers(ctx, before)
+	if err != nil {
+		return err
+	}
+	if len(uuids) > 0 {
+		logging.Info("anonymized %d withdrawn appusers (withdrawn before %s)", len(uuids), before.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// purgeExpiredRefreshTokens : 만료된 refresh token 삭제. 만료 token 은 재사용 감지 없이도 거부되므로 보관할 필요 없음
+func purgeExpiredRefreshTokens(ctx context.Context) error {
+	affected, err := models.RefreshToken.DeleteExpiredRefreshTokens(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		logging.Info("purged %d expired refresh tokens", affected)
+	}
+
+	return nil
+}
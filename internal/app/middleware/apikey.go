@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"fiber-boilerplate/internal/app/handlers"
+	"fiber-boilerplate/internal/defs"
+	"fiber-boilerplate/internal/models"
+	"fiber-boilerplate/internal/pkg/auth"
+	"fiber-boilerplate/internal/pkg/authz"
+	logging "fiber-boilerplate/internal/pkg/logging"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+)
+
+// OpenAPI securitySchemes 이름
+const (
+	securitySchemeJWT       = "jwtAuth"
+	securitySchemeAPIKey    = "apiKeyAuth"
+	securitySchemeSSETicket = "sseTicket"
+)
+
+const (
+	// headerAPIKey : apiKeyAuth 의 header 이름
+	headerAPIKey = "X-API-Key"
+	// queryTicket : sseTicket 의 query 이름
+	queryTicket = "ticket"
+)
+
+// oapiScheme : operation 이 선언한 scheme 중 이 요청에 사용할 것.
+// 둘 다 선언돼 있으면 X-API-Key 헤더 (sseTicket 은 ticket query) 가 있을 때만 API key (ticket)
+func oapiScheme(ctx *fiber.Ctx, operation *openapi3.Operation) string {
+	declared := map[string]bool{}
+	for _, security := range *operation.Security {
+		for name := range security {
+			declared[name] = true
+		}
+	}
+
+	switch {
+	case declared[securitySchemeAPIKey] && ctx.Get(headerAPIKey) != "":
+		return securitySchemeAPIKey
+	case declared[securitySchemeSSETicket] && ctx.Query(queryTicket) != "":
+		return securitySchemeSSETicket
+	case declared[securitySchemeJWT]:
+		return securitySchemeJWT
+	case declared[securitySchemeAPIKey]:
+		return securitySchemeAPIKey
+	default:
+		return ""
+	}
+}
+
+// apiKeyAuth : operation 이 apiKeyAuth 로 인증하는 경우 key 를 검증하고 synthetic principal 을 저장.
+// API key 요청은 JWT 클레임이 없으므로 appuser 세션을 만들지 않는다
+func apiKeyAuth(ctx *fiber.Ctx) error {
+	if scheme, _ := ctx.Locals("oapi:scheme").(string); scheme != securitySchemeAPIKey {
+		return ctx.Next()
+	}
+
+	key := ctx.Get(headerAPIKey)
+	if key == "" {
+		return handlers.SendError(ctx, http.StatusUnauthorized, defs.ErrUnauthenticated)
+	}
+
+	entity, err := models.ApiKey.GetApiKeyByHash(ctx.Context(), auth.HashAPIKey(key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return handlers.SendError(ctx, http.StatusUnauthorized, defs.ErrInvalidAPIKey)
+	}
+	if err != nil {
+		return handlers.SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to get api key: %w", err))
+	}
+	if entity.RevokedAt.Valid || (entity.ExpiresAt.Valid && !time.Now().Before(entity.ExpiresAt.Time)) {
+		logging.Debug("api key rejected: uuid=%s, revoked=%v, expiresAt=%v", entity.UUID.String, entity.RevokedAt.Valid, entity.ExpiresAt.Time)
+		return handlers.SendError(ctx, http.StatusUnauthorized, defs.ErrInvalidAPIKey)
+	}
+
+	// last_used_at 갱신 실패로 요청을 막지는 않음
+	if _, err := models.ApiKey.TouchApiKey(ctx.Context(), entity.ID.Int64); err != nil {
+		logging.Warn(err, "failed to update api key last_used_at: %s", entity.UUID.String)
+	}
+
+	ctx.Locals(authz.ContextKeyPrincipal, &authz.PrincipalBlock{
+		Kind:  authz.PrincipalAPIKey,
+		ID:    entity.UUID.String,
+		Name:  entity.Name.String,
+		Owner: entity.Owner.String,
+	})
+	ctx.Locals(authz.ContextKeyPermission, &authz.PermissionBlock{
+		Scopes: append([]string{}, entity.Scopes...),
+	})
+
+	return ctx.Next()
+}
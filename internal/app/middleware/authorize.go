@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"fiber-boilerplate/internal/app/handlers"
+	"fiber-boilerplate/internal/defs"
+	"fiber-boilerplate/internal/pkg/authz"
+	logging "fiber-boilerplate/internal/pkg/logging"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// extensionRoles : operation 에 필요한 role 목록 (하나만 있으면 됨)
+const extensionRoles = "x-roles"
+
+// oapiRequirement : operation 의 security requirement 중 scheme 을 포함하는 것의 scope 와 x-roles.
+// role 은 appuser 의 것이므로 x-roles 는 JWT 로 인증한 요청에만 적용
+func oapiRequirement(operation *openapi3.Operation, scheme string) *authz.RequirementBlock {
+	requirement := new(authz.RequirementBlock)
+
+	if operation.Security != nil {
+		for _, security := range *operation.Security {
+			if _, ok := security[scheme]; !ok {
+				continue
+			}
+			var scopes []string
+			for _, schemeScopes := range security {
+				scopes = append(scopes, schemeScopes...)
+			}
+			requirement.Scopes = append(requirement.Scopes, scopes)
+		}
+	}
+
+	if scheme != securitySchemeJWT {
+		return requirement
+	}
+	if roles, ok := operation.Extensions[extensionRoles].([]interface{}); ok {
+		for _, role := range roles {
+			if s, ok := role.(string); ok {
+				requirement.Roles = append(requirement.Roles, s)
+			}
+		}
+	}
+
+	return requirement
+}
+
+// authorize : 토큰의 scope / roles 클레임 또는 API key 의 scope 가 operation 의 요구 권한을 만족하는지 확인
+// (keyauth, apiKeyAuth 다음에 실행)
+func authorize(ctx *fiber.Ctx) error {
+	// API key 요청은 apiKeyAuth 에서 key 의 scope 로 권한을 저장함
+	permission, _ := ctx.Locals(authz.ContextKeyPermission).(*authz.PermissionBlock)
+	if !authz.PrincipalFromContext(ctx).IsAPIKey() {
+		claims, _ := ctx.Locals(ContextKeyStore).(jwt.MapClaims)
+		permission = authz.FromClaims(claims)
+		ctx.Locals(authz.ContextKeyPermission, permission)
+		if uuid, _ := claims["uuid"].(string); uuid != "" {
+			ctx.Locals(authz.ContextKeyPrincipal, &authz.PrincipalBlock{Kind: authz.PrincipalAppuser, ID: uuid})
+		}
+	}
+
+	if skipAuth, ok := ctx.Locals("oapi:skip_auth").(bool); ok && skipAuth {
+		return ctx.Next()
+	}
+	requirement, ok := ctx.Locals("oapi:requirement").(*authz.RequirementBlock)
+	if !ok {
+		// spec 에 없는 경로
+		return ctx.Next()
+	}
+
+	if ok, missing := permission.Satisfies(requirement); !ok {
+		logging.Debug("authorization failed: path=%s, missing=%v, scopes=%v, roles=%v", ctx.Path(), missing, permission.Scopes, permission.Roles)
+		return handlers.SendError(ctx, http.StatusForbidden, defs.ErrInsufficientPermission.WithMessage("missing permission: %s", strings.Join(missing, ", ")))
+	}
+
+	return ctx.Next()
+}
@@ -3,9 +3,13 @@ package middleware
 import (
 	"errors"
 	"strings"
+	"time"
 
 	"fiber-boilerplate/internal/app/config"
+	"fiber-boilerplate/internal/defs"
+	"fiber-boilerplate/internal/pkg/jwks"
 	logging "fiber-boilerplate/internal/pkg/logging"
+	"fiber-boilerplate/internal/pkg/revocation"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/keyauth"
@@ -14,6 +18,62 @@ import (
 
 const ContextKeyStore = "fiber-boilerplate/#/keyStore"
 
+var (
+	// jwtKeySet : JWT_PUBLIC_KEYS, JWT_JWKS 가 설정된 경우에만 생성
+	jwtKeySet        *jwks.KeySetBlock
+	jwtParserOptions []jwt.ParserOption
+)
+
+// setupJWT : 허용 알고리즘, 공개키, iss / aud 검증 설정
+func setupJWT() {
+	var methods []string
+	if config.Server.JwtSecret != "" {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if len(config.Server.JWT.PublicKeys) > 0 || config.Server.JWT.JWKS != "" {
+		keySet, err := jwks.New(jwks.ConfigBlock{
+			PEMFiles: config.Server.JWT.PublicKeys,
+			JWKS:     config.Server.JWT.JWKS,
+			Refresh:  time.Duration(config.Server.JWT.JWKSRefresh) * time.Second,
+		})
+		if err != nil {
+			panic(err)
+		}
+		jwtKeySet = keySet
+		methods = append(methods, jwks.Algorithms...)
+		logging.Info("JWT public keys loaded: %d", keySet.Len())
+	}
+
+	jwtParserOptions = []jwt.ParserOption{
+		jwt.WithValidMethods(methods), // 설정된 키 종류의 알고리즘만 허용
+		jwt.WithExpirationRequired(),  // exp 클레임 필수 및 자동 검증
+	}
+	if config.Server.JWT.Issuer != "" {
+		jwtParserOptions = append(jwtParserOptions, jwt.WithIssuer(config.Server.JWT.Issuer))
+	}
+	if len(config.Server.JWT.Audience) > 0 {
+		jwtParserOptions = append(jwtParserOptions, jwt.WithAudience(config.Server.JWT.Audience...))
+	}
+}
+
+// jwtKeyfunc : HS256 은 JWT_SECRET, 그 외는 kid 로 공개키 선택
+func jwtKeyfunc(t *jwt.Token) (interface{}, error) {
+	// 서명 알고리즘 확인 (보안상 중요)
+	switch t.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if config.Server.JwtSecret == "" {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(config.Server.JwtSecret), nil
+	default:
+		if jwtKeySet == nil {
+			return nil, errors.New("unexpected signing method")
+		}
+		return jwtKeySet.Keyfunc(t)
+	}
+}
+
 func validateAPIKey(c *fiber.Ctx, tokenString string) (bool, error) {
 	// keyauth가 Authorization 헤더에서 Bearer를 제거하지 못했을 경우 수동 처리
 	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
@@ -23,19 +83,10 @@ func validateAPIKey(c *fiber.Ctx, tokenString string) (bool, error) {
 	}
 
 	// JWT 파서 생성 (자동 expiration 검증 포함)
-	parser := jwt.NewParser(
-		jwt.WithValidMethods([]string{"HS256"}), // HS256만 허용
-		jwt.WithExpirationRequired(),            // exp 클레임 필수 및 자동 검증
-	)
+	parser := jwt.NewParser(jwtParserOptions...)
 
 	// JWT 파싱 및 검증
-	token, err := parser.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		// 서명 알고리즘 확인 (보안상 중요)
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return []byte(config.Server.JwtSecret), nil
-	})
+	token, err := parser.Parse(tokenString, jwtKeyfunc)
 	if err != nil || !token.Valid {
 		logging.Debug("JWT validation failed: err=%v, valid=%v", err, token.Valid)
 		return false, err
@@ -43,6 +94,11 @@ func validateAPIKey(c *fiber.Ctx, tokenString string) (bool, error) {
 
 	// 클레임을 컨텍스트에 저장
 	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		// 폐기 목록 확인 (jti 단위, 세션 단위, 사용자 단위)
+		if err := checkRevocation(c, claims); err != nil {
+			return false, err
+		}
+
 		/*
 			{
 				"uuid": uuid string
@@ -55,3 +111,16 @@ func validateAPIKey(c *fiber.Ctx, tokenString string) (bool, error) {
 
 	return true, nil
 }
+
+// checkRevocation : 폐기 목록을 확인하지 못하면 통과시키지 않고 503
+func checkRevocation(c *fiber.Ctx, claims jwt.MapClaims) error {
+	err := revocation.CheckClaims(c.Context(), claims)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, revocation.ErrRevoked):
+		return defs.ErrTokenRevoked.Wrap(err)
+	default:
+		return defs.ErrRevocationCheck.Wrap(err)
+	}
+}
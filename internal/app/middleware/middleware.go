@@ -1,16 +1,20 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"runtime/debug"
 	"strings"
+	"time"
 
 	"fiber-boilerplate/internal/app/config"
 	"fiber-boilerplate/internal/app/handlers"
+	"fiber-boilerplate/internal/defs"
 	logging "fiber-boilerplate/internal/pkg/logging"
 	"fiber-boilerplate/internal/pkg/session"
 	"fiber-boilerplate/internal/pkg/setting"
 
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -27,7 +31,7 @@ import (
 type LoggerWriter struct{}
 
 func (lw *LoggerWriter) Write(p []byte) (n int, err error) {
-	logging.Info(string(p))
+	logging.Info("%s", p)
 	return len(p), nil
 }
 
@@ -67,12 +71,15 @@ func Register(f *fiber.App) {
 	// Generates ETags for efficient HTTP caching and bandwidth reduction
 	f.Use(etag.New(etag.Config{
 		Weak: false,
+		// stream 응답은 body 를 읽으면 끝날 때까지 기다리게 됨
+		Next: isStream,
 	}))
 
 	// Compress: gzip/deflate/brotli로 응답 압축 (기본 압축 레벨)
 	// Compresses responses to reduce bandwidth usage
 	f.Use(compress.New(compress.Config{
 		Level: compress.LevelDefault,
+		Next:  isStream,
 	}))
 
 	// Pprof: 성능 프로파일링 (개발/로컬 환경만, /debug/pprof)
@@ -95,17 +102,40 @@ func Register(f *fiber.App) {
 		}))
 	}
 
-	// OpenAPI Validation: 요청이 OpenAPI 스펙을 준수하는지 검증
+	// OpenAPI Validation: 요청이 OpenAPI 스펙을 준수하는지 검증 (설정에 따라 응답도 검증)
 	// Validates requests against OpenAPI specification (body, params, headers)
+	// and optionally responses (OAPI_RESPONSE_VALIDATION=off|log|fail)
+	setupResponseValidation()
 	f.Use(oapiRequestValidate)
 
+	// API Key: operation 이 apiKeyAuth 를 선언했고 X-API-Key 로 요청한 경우 key 검증 (JWT 대신)
+	// Validates X-API-Key for operations declaring apiKeyAuth and sets a synthetic principal
+	f.Use(apiKeyAuth)
+
+	// SSE Ticket: /sse/open, /ws 를 ticket query 로 요청한 경우 ticket 을 발급한 토큰의 클레임 사용 (EventSource 는 헤더를 보낼 수 없음)
+	// Redeems the ticket query of /sse/open and /ws and stores the claims of the token that issued it
+	f.Use(sseTicketAuth)
+
 	// Key Auth (JWT): Authorization 헤더에서 Bearer 토큰 추출 및 검증
 	// Extracts and validates JWT tokens from Authorization header
-	// OpenAPI의 security 필드에 따라 인증 스킵 여부 결정 (oapi:skip_auth)
+	// HS256 (JWT_SECRET) 과 RS/PS/ES/EdDSA (JWT_PUBLIC_KEYS, JWT_JWKS) 지원
+	setupJWT()
+	// OpenAPI의 security 필드에 따라 인증 스킵 여부 결정 (oapi:skip_auth, oapi:scheme)
 	f.Use(keyauth.New(keyauth.Config{
 		KeyLookup:  "header:Authorization",
 		AuthScheme: "Bearer",
 		Validator:  validateAPIKey,
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			// 폐기된 토큰(401), 폐기 목록 확인 실패(503)
+			var appErr *defs.AppError
+			if errors.As(err, &appErr) {
+				return handlers.SendError(c, appErr.Status, err)
+			}
+			if errors.Is(err, keyauth.ErrMissingOrMalformedAPIKey) {
+				return handlers.SendError(c, fiber.StatusUnauthorized, defs.ErrUnauthenticated.Wrap(err))
+			}
+			return handlers.SendError(c, fiber.StatusUnauthorized, defs.ErrInvalidToken.Wrap(err))
+		},
 		Next: func(c *fiber.Ctx) bool {
 			skipAuth, ok := c.Locals("oapi:skip_auth").(bool)
 			logging.Debug("keyauth Next: path=%s, skipAuth=%v, ok=%v", c.Path(), skipAuth, ok)
@@ -113,13 +143,28 @@ func Register(f *fiber.App) {
 				logging.Debug("Skipping keyauth for path: %s", c.Path())
 				return true
 			}
+			if scheme, _ := c.Locals("oapi:scheme").(string); scheme == securitySchemeAPIKey || scheme == securitySchemeSSETicket {
+				// apiKeyAuth, sseTicketAuth 에서 인증함
+				return true
+			}
 			logging.Debug("Running keyauth for path: %s", c.Path())
 			return false
 		},
 	}))
 
+	// Authorization: OpenAPI security requirement 의 scope 와 x-roles 를 토큰의 scope / roles 클레임 (API key 는 key 의 scope) 과 비교 (부족하면 403)
+	// Checks scopes and x-roles of the operation against the token's scope / roles claims
+	f.Use(authorize)
+
 	// Session: JWT의 uuid 클레임으로 DB에서 사용자 정보 로드
 	// Loads user session from database using JWT uuid claim
+	// 사용하지 않으면 SESSION_IDLE_TIMEOUT 후, 사용해도 SESSION_MAX_LIFETIME 후 만료되어 DB 에서 다시 로드
+	session.Configure(ContextKeyStore, session.ConfigBlock{
+		IdleTimeout:   time.Duration(config.Server.Session.IdleTimeout) * time.Second,
+		MaxLifetime:   time.Duration(config.Server.Session.MaxLifetime) * time.Second,
+		TouchInterval: time.Duration(config.Server.Session.TouchInterval) * time.Second,
+		Codec:         config.Server.Session.Codec,
+	})
 	sessionMiddleware := session.Middleware(ContextKeyStore, validate, handlers.SendError)
 	f.Use(func(ctx *fiber.Ctx) error {
 		handler := sessionMiddleware(func(c *fiber.Ctx) error {
@@ -128,3 +173,9 @@ func Register(f *fiber.App) {
 		return handler(ctx)
 	})
 }
+
+// isStream : SSE, WebSocket 요청. 이벤트마다 바로 보내야 하므로 응답 body 를 다루는 미들웨어를 건너뜀
+func isStream(c *fiber.Ctx) bool {
+	return strings.HasPrefix(c.Path(), "/api/sse/open") || strings.Contains(c.Get(fiber.HeaderAccept), "text/event-stream") ||
+		websocket.IsWebSocketUpgrade(c)
+}
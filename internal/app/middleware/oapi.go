@@ -1,15 +1,19 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"strings"
 
+	"fiber-boilerplate/internal/app/config"
 	"fiber-boilerplate/internal/app/handlers"
 	"fiber-boilerplate/internal/defs"
 	api "fiber-boilerplate/internal/generated/serviceapi"
 	logging "fiber-boilerplate/internal/pkg/logging"
+	"fiber-boilerplate/internal/pkg/setting"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
@@ -19,8 +23,18 @@ import (
 	"github.com/valyala/fasthttp/fasthttpadaptor"
 )
 
+// response validation modes
+const (
+	responseValidationOff  = "off"
+	responseValidationLog  = "log"
+	responseValidationFail = "fail"
+)
+
 var (
 	oapiRouter routers.Router
+
+	// oapiResponseValidation : Register 에서 설정 (setupResponseValidation)
+	oapiResponseValidation = responseValidationOff
 )
 
 func init() {
@@ -50,8 +64,14 @@ func oapiAuthenticationFunc(ctx *fiber.Ctx, skipAuth bool) func(c context.Contex
 				return defs.ErrUnauthorized
 			}
 		}
-		if input.SecuritySchemeName == "appEngineCron" {
-			if input.RequestValidationInput.Request.Header.Get("X-Appengine-Cron") != "true" {
+		// API key 자체의 검증은 apiKeyAuth 에서, ticket 은 sseTicketAuth 에서
+		if input.SecurityScheme.Type == "apiKey" && input.SecurityScheme.In == "header" {
+			if input.RequestValidationInput.Request.Header.Get(input.SecurityScheme.Name) == "" {
+				return defs.ErrUnauthorized
+			}
+		}
+		if input.SecurityScheme.Type == "apiKey" && input.SecurityScheme.In == "query" {
+			if input.RequestValidationInput.Request.URL.Query().Get(input.SecurityScheme.Name) == "" {
 				return defs.ErrUnauthorized
 			}
 		}
@@ -59,6 +79,19 @@ func oapiAuthenticationFunc(ctx *fiber.Ctx, skipAuth bool) func(c context.Contex
 	}
 }
 
+// oapiAnonymous : operation 이 빈 security requirement ({ }) 로 인증 없는 요청을 허용하고 요청에 자격 증명이 없는 경우.
+// 자격 증명을 보냈으면 평소처럼 검증 (잘못된 토큰은 401)
+func oapiAnonymous(ctx *fiber.Ctx, operation *openapi3.Operation) bool {
+	optional := false
+	for _, security := range *operation.Security {
+		if len(security) == 0 {
+			optional = true
+		}
+	}
+
+	return optional && ctx.Get(fiber.HeaderAuthorization) == "" && ctx.Get(headerAPIKey) == "" && ctx.Query(queryTicket) == ""
+}
+
 func oapiRequestValidate(ctx *fiber.Ctx) error {
 	// Use our validation middleware to check all requests against the
 	// OpenAPI schema.
@@ -94,7 +127,7 @@ func oapiRequestValidate(ctx *fiber.Ctx) error {
 	// path를 OpenAPI base path 제외한 경로로 변경
 	r.URL.Path = pathWithoutAPI
 
-	route, _, errRoute := oapiRouter.FindRoute(r)
+	route, pathParams, errRoute := oapiRouter.FindRoute(r)
 	if errors.Is(errRoute, routers.ErrPathNotFound) {
 		logging.Debug("Path not found in OpenAPI spec: %s", ctx.Path())
 		return ctx.Next()
@@ -111,7 +144,14 @@ func oapiRequestValidate(ctx *fiber.Ctx) error {
 			skipAuth = true
 			ctx.Locals("oapi:skip_auth", true)
 			logging.Debug("Skip auth for path: %s (no security required)", ctx.Path())
+		} else if oapiAnonymous(ctx, route.Operation) {
+			skipAuth = true
+			ctx.Locals("oapi:skip_auth", true)
+			logging.Debug("Skip auth for path: %s (anonymous allowed, no credentials)", ctx.Path())
 		} else {
+			scheme := oapiScheme(ctx, route.Operation)
+			ctx.Locals("oapi:scheme", scheme)
+			ctx.Locals("oapi:requirement", oapiRequirement(route.Operation, scheme))
 			logging.Debug("Auth required for path: %s, security: %+v", ctx.Path(), route.Operation.Security)
 		}
 	} else {
@@ -120,18 +160,132 @@ func oapiRequestValidate(ctx *fiber.Ctx) error {
 
 	// OpenAPI validation 수행 - 수정된 request 사용
 	requestValidationInput := &openapi3filter.RequestValidationInput{
-		Request: r,
-		Route:   route,
+		Request:    r,
+		PathParams: pathParams,
+		Route:      route,
 		Options: &openapi3filter.Options{
 			AuthenticationFunc: oapiAuthenticationFunc(ctx, skipAuth),
+			MultiError:         true,
 		},
 	}
 
 	err = openapi3filter.ValidateRequest(rctx, requestValidationInput)
 	if err != nil {
 		logging.Debug("OpenAPI validation error for path %s: %v", ctx.Path(), err)
-		return handlers.SendError(ctx, http.StatusBadRequest, err)
+		return handlers.SendError(ctx, http.StatusBadRequest, oapiValidationError(err))
+	}
+
+	err = ctx.Next()
+	if err != nil || route == nil || oapiResponseValidation == responseValidationOff {
+		return err
+	}
+
+	return oapiResponseValidate(ctx, requestValidationInput)
+}
+
+// setupResponseValidation : OAPI_RESPONSE_VALIDATION 이 비어 있으면 local, development 에서만 log
+func setupResponseValidation() {
+	mode := strings.ToLower(strings.TrimSpace(config.Server.ResponseValidation))
+	switch mode {
+	case responseValidationOff, responseValidationLog, responseValidationFail:
+	case "":
+		mode = responseValidationOff
+		if setting.Runtime.Env == "local" || setting.Runtime.Env == "development" {
+			mode = responseValidationLog
+		}
+	default:
+		logging.Warn(defs.ErrInvalid, "unknown OAPI_RESPONSE_VALIDATION: %q, response validation disabled", mode)
+		mode = responseValidationOff
+	}
+
+	oapiResponseValidation = mode
+	if mode != responseValidationOff {
+		logging.Info("OpenAPI response validation enabled (mode: %s)", mode)
+	}
+}
+
+// oapiResponseValidate : handler 가 만든 응답을 spec 과 비교. fail 모드면 500 으로 교체
+func oapiResponseValidate(ctx *fiber.Ctx, requestValidationInput *openapi3filter.RequestValidationInput) error {
+	response := ctx.Response()
+	// SSE 등 stream 응답과 body 가 없는 304 는 검증하지 않음
+	if response.IsBodyStream() || response.StatusCode() == http.StatusNotModified {
+		return nil
+	}
+
+	header := http.Header{}
+	response.Header.VisitAll(func(key, value []byte) {
+		header.Add(string(key), string(value))
+	})
+
+	err := openapi3filter.ValidateResponse(ctx.Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: requestValidationInput,
+		Status:                 response.StatusCode(),
+		Header:                 header,
+		Body:                   io.NopCloser(bytes.NewReader(response.Body())),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			MultiError:            true,
+		},
+	})
+	if err == nil {
+		return nil
+	}
+
+	logging.Warn(err, "response does not match the API specification: %s %s %d", ctx.Method(), ctx.Path(), response.StatusCode())
+	if oapiResponseValidation != responseValidationFail {
+		return nil
+	}
+
+	response.Header.Del(fiber.HeaderETag)
+	return handlers.SendError(ctx, http.StatusInternalServerError, defs.ErrResponseValidation.Wrap(err))
+}
+
+// oapiValidationError : kin-openapi 검증 에러를 필드 단위 violation 으로 변환
+func oapiValidationError(err error) error {
+	var securityErr *openapi3filter.SecurityRequirementsError
+	if errors.As(err, &securityErr) {
+		return defs.ErrUnauthenticated.Wrap(err)
+	}
+
+	return defs.ErrValidationFailed.WithViolations(oapiViolations(err, "")...).Wrap(err)
+}
+
+// oapiViolations :
+// MultiError 의 As 가 원소까지 매칭하므로 errors.As 대신 type switch 로 한 단계씩 내려간다
+func oapiViolations(err error, field string) (violations []defs.Violation) {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		for _, inner := range e {
+			violations = append(violations, oapiViolations(inner, field)...)
+		}
+	case *openapi3filter.RequestError:
+		switch {
+		case e.Parameter != nil:
+			field = e.Parameter.In + "." + e.Parameter.Name
+		case e.RequestBody != nil:
+			field = "body"
+		}
+		if e.Err == nil {
+			violations = append(violations, defs.Violation{Field: field, Message: e.Reason})
+		} else {
+			violations = append(violations, oapiViolations(e.Err, field)...)
+		}
+	case *openapi3.SchemaError:
+		// SchemaError.Reason 은 입력 값을 포함하지 않음
+		if pointer := e.JSONPointer(); len(pointer) > 0 {
+			field = strings.Join(append([]string{field}, pointer...), ".")
+		}
+		violations = append(violations, defs.Violation{Field: field, Message: e.Reason})
+	case *openapi3filter.ParseError:
+		// ParseError.Reason 은 비어 있는 경우가 있음 (json decode 실패 등)
+		message := e.Reason
+		if message == "" {
+			message = "value could not be parsed"
+		}
+		violations = append(violations, defs.Violation{Field: field, Message: message})
+	default:
+		violations = append(violations, defs.Violation{Field: field, Message: "invalid value"})
 	}
 
-	return ctx.Next()
+	return
 }
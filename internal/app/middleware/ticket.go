@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"fiber-boilerplate/internal/app/handlers"
+	"fiber-boilerplate/internal/defs"
+	"fiber-boilerplate/internal/pkg/realtime"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// sseTicketAuth : operation 이 sseTicket 으로 인증하는 경우 ticket 을 발급한 토큰의 클레임을 JWT 처럼 저장.
+// ticket 을 다시 쓰는 동안 토큰이 폐기될 수 있으므로 요청마다 폐기 목록 확인
+func sseTicketAuth(ctx *fiber.Ctx) error {
+	if scheme, _ := ctx.Locals("oapi:scheme").(string); scheme != securitySchemeSSETicket {
+		return ctx.Next()
+	}
+
+	claims, err := realtime.RedeemTicket(ctx.Context(), ctx.Query(queryTicket))
+	if errors.Is(err, defs.ErrInvalidTicket) {
+		return handlers.SendError(ctx, http.StatusUnauthorized, err)
+	}
+	if err != nil {
+		return handlers.SendError(ctx, http.StatusInternalServerError, fmt.Errorf("failed to redeem ticket: %w", err))
+	}
+
+	// 폐기된 토큰(401), 폐기 목록 확인 실패(503)
+	if err := checkRevocation(ctx, claims); err != nil {
+		return handlers.SendError(ctx, http.StatusUnauthorized, err)
+	}
+
+	ctx.Locals(ContextKeyStore, claims)
+
+	return ctx.Next()
+}
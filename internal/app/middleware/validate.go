@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 
+	"fiber-boilerplate/internal/defs"
 	"fiber-boilerplate/internal/models"
 	"fiber-boilerplate/internal/pkg/session"
 
@@ -13,7 +14,7 @@ import (
 )
 
 func validate(ctx *fiber.Ctx, sessionKey string) (int, *session.DataBlock, error) {
-	entity, err := models.Appuser.SearchAppusers(ctx.Context(), models.SearchAppusersParams{
+	entity, err := models.Appuser.SearchAppusers(nil, ctx.Context(), models.SearchAppusersParams{
 		UUID: null.StringFrom(sessionKey),
 	})
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -22,6 +23,10 @@ func validate(ctx *fiber.Ctx, sessionKey string) (int, *session.DataBlock, error
 	if len(entity) == 0 {
 		return http.StatusUnauthorized, nil, nil
 	}
+	if entity[0].Withdraw.Bool {
+		// 탈퇴한 사용자는 토큰이 유효해도 거부
+		return http.StatusForbidden, nil, defs.ErrAppuserWithdrawn
+	}
 
 	return http.StatusOK, session.New(&entity[0]), nil
 }
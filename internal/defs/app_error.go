@@ -0,0 +1,113 @@
+package defs
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// AppError : 클라이언트에 노출되는 application error.
+// Code 는 클라이언트가 분기할 수 있는 고정 값, Message 는 노출해도 안전한 문구이고 Cause 는 서버 로그에만 남는다
+type AppError struct {
+	Status     int
+	Code       string
+	Message    string
+	Violations []Violation
+	Cause      error
+}
+
+// Violation : 필드 단위 검증 실패
+type Violation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// application errors
+var (
+	ErrMalformedBody          = NewAppError(http.StatusBadRequest, "malformed_body", "request body could not be parsed")
+	ErrInvalidParameter       = NewAppError(http.StatusBadRequest, "invalid_parameter", "request parameter is invalid")
+	ErrValidationFailed       = NewAppError(http.StatusBadRequest, "validation_failed", "request validation failed")
+	ErrUnauthenticated        = NewAppError(http.StatusUnauthorized, "unauthenticated", "authentication is required")
+	ErrInvalidToken           = NewAppError(http.StatusUnauthorized, "invalid_token", "token is invalid or expired")
+	ErrInsufficientPermission = NewAppError(http.StatusForbidden, "insufficient_permission", "token lacks the permission required for this operation")
+	ErrAppuserWithdrawn       = NewAppError(http.StatusForbidden, "appuser_withdrawn", "appuser has withdrawn")
+	ErrAppuserNotFound        = NewAppError(http.StatusNotFound, "appuser_not_found", "appuser not found")
+	ErrVersionConflict        = NewAppError(http.StatusConflict, "version_conflict", "resource was modified by another request")
+	ErrRecordNotFound         = NewAppError(http.StatusNotFound, "not_found", "resource not found")
+	ErrDuplicateRecord        = NewAppError(http.StatusConflict, "duplicate", "resource already exists")
+	ErrReferenceConflict      = NewAppError(http.StatusConflict, "reference_conflict", "resource references or is referenced by another resource")
+	ErrConcurrentUpdate       = NewAppError(http.StatusConflict, "concurrent_update", "resource is being modified concurrently, retry the request")
+	ErrResponseValidation     = NewAppError(http.StatusInternalServerError, "response_validation_failed", "response does not match the API specification")
+	ErrInvalidCredentials     = NewAppError(http.StatusUnauthorized, "invalid_credentials", "login or password is incorrect")
+	ErrInvalidRefresh         = NewAppError(http.StatusUnauthorized, "invalid_refresh_token", "refresh token is invalid, expired or revoked")
+	ErrRefreshReused          = NewAppError(http.StatusUnauthorized, "refresh_token_reused", "refresh token was already used, all sessions of this login were revoked")
+	ErrSigningDisabled        = NewAppError(http.StatusNotImplemented, "token_issuance_disabled", "this server does not issue tokens")
+	ErrTokenRevoked           = NewAppError(http.StatusUnauthorized, "token_revoked", "token has been revoked")
+	ErrRevocationCheck        = NewAppError(http.StatusServiceUnavailable, "revocation_unavailable", "token revocation could not be checked, retry the request")
+	ErrRevocationWrite        = NewAppError(http.StatusServiceUnavailable, "revocation_unavailable", "token revocation could not be recorded")
+	ErrInvalidAPIKey          = NewAppError(http.StatusUnauthorized, "invalid_api_key", "api key is invalid, expired or revoked")
+	ErrInvalidTicket          = NewAppError(http.StatusUnauthorized, "invalid_ticket", "ticket is invalid or expired")
+)
+
+// NewAppError :
+func NewAppError(status int, code string, message string) *AppError {
+	return &AppError{
+		Status:  status,
+		Code:    code,
+		Message: message,
+	}
+}
+
+// StatusAppError : 정의된 AppError 가 없을 때 status 로 만드는 기본 error (code 는 status text 의 snake_case)
+func StatusAppError(status int) *AppError {
+	code := make([]rune, 0, len(http.StatusText(status)))
+	for _, r := range http.StatusText(status) {
+		switch {
+		case r == ' ' || r == '-':
+			code = append(code, '_')
+		case r >= 'A' && r <= 'Z':
+			code = append(code, r+('a'-'A'))
+		case r >= 'a' && r <= 'z':
+			code = append(code, r)
+		}
+	}
+
+	return NewAppError(status, string(code), http.StatusText(status))
+}
+
+func (e *AppError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %v", e.Code, e.Cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Cause
+}
+
+// Is : 같은 Code 면 같은 error 로 취급 (errors.Is(err, defs.ErrAppuserNotFound))
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
+}
+
+// Wrap : 서버 로그용 원인 error 를 붙인 복사본
+func (e *AppError) Wrap(cause error) *AppError {
+	c := *e
+	c.Cause = cause
+	return &c
+}
+
+// WithMessage : 클라이언트에 보여줄 message 를 바꾼 복사본. 내부 정보가 들어가지 않도록 주의
+func (e *AppError) WithMessage(format string, values ...interface{}) *AppError {
+	c := *e
+	c.Message = fmt.Sprintf(format, values...)
+	return &c
+}
+
+// WithViolations : 필드 단위 검증 실패를 붙인 복사본
+func (e *AppError) WithViolations(violations ...Violation) *AppError {
+	c := *e
+	c.Violations = append(append([]Violation{}, e.Violations...), violations...)
+	return &c
+}
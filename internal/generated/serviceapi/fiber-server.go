@@ -15,6 +15,21 @@ import (
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
+	// (POST /admin/api-key/create)
+	CreateApiKey(c *fiber.Ctx) error
+
+	// (GET /admin/api-key/list)
+	ListApiKeys(c *fiber.Ctx) error
+
+	// (POST /admin/api-key/{uuid}/revoke)
+	RevokeApiKey(c *fiber.Ctx, uuid UuidPathParam) error
+
+	// (POST /admin/appuser/{uuid}/revoke-tokens)
+	RevokeAppuserTokens(c *fiber.Ctx, uuid UuidPathParam) error
+
+	// (POST /admin/token/revoke)
+	RevokeToken(c *fiber.Ctx) error
+
 	// (POST /appuser/create)
 	CreateAppuser(c *fiber.Ctx) error
 
@@ -24,14 +39,53 @@ type ServerInterface interface {
 	// (PUT /appuser/update)
 	UpdateAppuser(c *fiber.Ctx) error
 
+	// (DELETE /appuser/{uuid})
+	DeleteAppuser(c *fiber.Ctx, uuid UuidPathParam) error
+
+	// (GET /appuser/{uuid})
+	GetAppuser(c *fiber.Ctx, uuid UuidPathParam) error
+
+	// (PATCH /appuser/{uuid})
+	PatchAppuser(c *fiber.Ctx, uuid UuidPathParam) error
+
+	// (POST /appuser/{uuid}/withdraw)
+	WithdrawAppuser(c *fiber.Ctx, uuid UuidPathParam) error
+
+	// (POST /auth/login)
+	Login(c *fiber.Ctx) error
+
+	// (POST /auth/logout)
+	Logout(c *fiber.Ctx) error
+
+	// (POST /auth/refresh)
+	RefreshToken(c *fiber.Ctx) error
+
+	// (GET /health)
+	GetHealth(c *fiber.Ctx) error
+
 	// (GET /ping)
 	GetPing(c *fiber.Ctx) error
 
-	// (GET /sse/close)
-	SseClose(c *fiber.Ctx) error
+	// (POST /realtime/publish)
+	PublishEvent(c *fiber.Ctx) error
+
+	// (GET /session/list)
+	ListSessions(c *fiber.Ctx) error
+
+	// (POST /session/revoke-others)
+	RevokeOtherSessions(c *fiber.Ctx) error
+
+	// (POST /session/{sessionId}/revoke)
+	RevokeSession(c *fiber.Ctx, sessionId SessionIdPathParam) error
 
 	// (GET /sse/open)
-	SseOpen(c *fiber.Ctx) error
+	SseOpen(c *fiber.Ctx, params SseOpenParams) error
+
+	// (POST /sse/ticket)
+	IssueSseTicket(c *fiber.Ctx) error
+
+	// (GET /ws)
+	WsOpen(c *fiber.Ctx, params WsOpenParams) error
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -41,10 +95,70 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc fiber.Handler
 
+// CreateApiKey operation middleware
+func (siw *ServerInterfaceWrapper) CreateApiKey(c *fiber.Ctx) error {
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{})
+
+	return siw.Handler.CreateApiKey(c)
+}
+
+// ListApiKeys operation middleware
+func (siw *ServerInterfaceWrapper) ListApiKeys(c *fiber.Ctx) error {
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{})
+
+	return siw.Handler.ListApiKeys(c)
+}
+
+// RevokeApiKey operation middleware
+func (siw *ServerInterfaceWrapper) RevokeApiKey(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "uuid" -------------
+	var uuid UuidPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uuid", c.Params("uuid"), &uuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter uuid: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{})
+
+	return siw.Handler.RevokeApiKey(c, uuid)
+}
+
+// RevokeAppuserTokens operation middleware
+func (siw *ServerInterfaceWrapper) RevokeAppuserTokens(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "uuid" -------------
+	var uuid UuidPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uuid", c.Params("uuid"), &uuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter uuid: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{})
+
+	return siw.Handler.RevokeAppuserTokens(c, uuid)
+}
+
+// RevokeToken operation middleware
+func (siw *ServerInterfaceWrapper) RevokeToken(c *fiber.Ctx) error {
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{})
+
+	return siw.Handler.RevokeToken(c)
+}
+
 // CreateAppuser operation middleware
 func (siw *ServerInterfaceWrapper) CreateAppuser(c *fiber.Ctx) error {
 
-	c.Context().SetUserValue(JwtAuthScopes, []string{})
+	c.Context().SetUserValue(JwtAuthScopes, []string{"appuser:write"})
 
 	return siw.Handler.CreateAppuser(c)
 }
@@ -54,7 +168,9 @@ func (siw *ServerInterfaceWrapper) ListAppusers(c *fiber.Ctx) error {
 
 	var err error
 
-	c.Context().SetUserValue(JwtAuthScopes, []string{})
+	c.Context().SetUserValue(JwtAuthScopes, []string{"appuser:read"})
+
+	c.Context().SetUserValue(ApiKeyAuthScopes, []string{"appuser:read"})
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params ListAppusersParams
@@ -127,27 +243,246 @@ func (siw *ServerInterfaceWrapper) ListAppusers(c *fiber.Ctx) error {
 // UpdateAppuser operation middleware
 func (siw *ServerInterfaceWrapper) UpdateAppuser(c *fiber.Ctx) error {
 
-	c.Context().SetUserValue(JwtAuthScopes, []string{})
+	c.Context().SetUserValue(JwtAuthScopes, []string{"appuser:write"})
 
 	return siw.Handler.UpdateAppuser(c)
 }
 
+// DeleteAppuser operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAppuser(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "uuid" -------------
+	var uuid UuidPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uuid", c.Params("uuid"), &uuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter uuid: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{"appuser:write"})
+
+	return siw.Handler.DeleteAppuser(c, uuid)
+}
+
+// GetAppuser operation middleware
+func (siw *ServerInterfaceWrapper) GetAppuser(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "uuid" -------------
+	var uuid UuidPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uuid", c.Params("uuid"), &uuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter uuid: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{"appuser:read"})
+
+	c.Context().SetUserValue(ApiKeyAuthScopes, []string{"appuser:read"})
+
+	return siw.Handler.GetAppuser(c, uuid)
+}
+
+// PatchAppuser operation middleware
+func (siw *ServerInterfaceWrapper) PatchAppuser(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "uuid" -------------
+	var uuid UuidPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uuid", c.Params("uuid"), &uuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter uuid: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{"appuser:write"})
+
+	return siw.Handler.PatchAppuser(c, uuid)
+}
+
+// WithdrawAppuser operation middleware
+func (siw *ServerInterfaceWrapper) WithdrawAppuser(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "uuid" -------------
+	var uuid UuidPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uuid", c.Params("uuid"), &uuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter uuid: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{"appuser:write"})
+
+	return siw.Handler.WithdrawAppuser(c, uuid)
+}
+
+// Login operation middleware
+func (siw *ServerInterfaceWrapper) Login(c *fiber.Ctx) error {
+
+	return siw.Handler.Login(c)
+}
+
+// Logout operation middleware
+func (siw *ServerInterfaceWrapper) Logout(c *fiber.Ctx) error {
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{})
+
+	return siw.Handler.Logout(c)
+}
+
+// RefreshToken operation middleware
+func (siw *ServerInterfaceWrapper) RefreshToken(c *fiber.Ctx) error {
+
+	return siw.Handler.RefreshToken(c)
+}
+
+// GetHealth operation middleware
+func (siw *ServerInterfaceWrapper) GetHealth(c *fiber.Ctx) error {
+
+	return siw.Handler.GetHealth(c)
+}
+
 // GetPing operation middleware
 func (siw *ServerInterfaceWrapper) GetPing(c *fiber.Ctx) error {
 
 	return siw.Handler.GetPing(c)
 }
 
-// SseClose operation middleware
-func (siw *ServerInterfaceWrapper) SseClose(c *fiber.Ctx) error {
+// PublishEvent operation middleware
+func (siw *ServerInterfaceWrapper) PublishEvent(c *fiber.Ctx) error {
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{})
+
+	c.Context().SetUserValue(ApiKeyAuthScopes, []string{"realtime:publish"})
+
+	return siw.Handler.PublishEvent(c)
+}
+
+// ListSessions operation middleware
+func (siw *ServerInterfaceWrapper) ListSessions(c *fiber.Ctx) error {
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{})
+
+	return siw.Handler.ListSessions(c)
+}
+
+// RevokeOtherSessions operation middleware
+func (siw *ServerInterfaceWrapper) RevokeOtherSessions(c *fiber.Ctx) error {
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{})
+
+	return siw.Handler.RevokeOtherSessions(c)
+}
+
+// RevokeSession operation middleware
+func (siw *ServerInterfaceWrapper) RevokeSession(c *fiber.Ctx) error {
+
+	var err error
+
+	// ------------- Path parameter "sessionId" -------------
+	var sessionId SessionIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "sessionId", c.Params("sessionId"), &sessionId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter sessionId: %w", err).Error())
+	}
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{})
 
-	return siw.Handler.SseClose(c)
+	return siw.Handler.RevokeSession(c, sessionId)
 }
 
 // SseOpen operation middleware
 func (siw *ServerInterfaceWrapper) SseOpen(c *fiber.Ctx) error {
 
-	return siw.Handler.SseOpen(c)
+	var err error
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{})
+
+	c.Context().SetUserValue(SseTicketScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SseOpenParams
+
+	var query url.Values
+	query, err = url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for query string: %w", err).Error())
+	}
+
+	// ------------- Required query parameter "topic" -------------
+
+	if paramValue := c.Query("topic"); paramValue != "" {
+
+	} else {
+		err = fmt.Errorf("Query argument topic is required, but not found")
+		c.Status(fiber.StatusBadRequest).JSON(err)
+		return err
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "topic", query, &params.Topic)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter topic: %w", err).Error())
+	}
+
+	// ------------- Optional query parameter "lastEventId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "lastEventId", query, &params.LastEventId)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter lastEventId: %w", err).Error())
+	}
+
+	return siw.Handler.SseOpen(c, params)
+}
+
+// IssueSseTicket operation middleware
+func (siw *ServerInterfaceWrapper) IssueSseTicket(c *fiber.Ctx) error {
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{})
+
+	return siw.Handler.IssueSseTicket(c)
+}
+
+// WsOpen operation middleware
+func (siw *ServerInterfaceWrapper) WsOpen(c *fiber.Ctx) error {
+
+	var err error
+
+	c.Context().SetUserValue(JwtAuthScopes, []string{})
+
+	c.Context().SetUserValue(SseTicketScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params WsOpenParams
+
+	var query url.Values
+	query, err = url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for query string: %w", err).Error())
+	}
+
+	// ------------- Required query parameter "topic" -------------
+
+	if paramValue := c.Query("topic"); paramValue != "" {
+
+	} else {
+		err = fmt.Errorf("Query argument topic is required, but not found")
+		c.Status(fiber.StatusBadRequest).JSON(err)
+		return err
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "topic", query, &params.Topic)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Errorf("Invalid format for parameter topic: %w", err).Error())
+	}
+
+	return siw.Handler.WsOpen(c, params)
 }
 
 // FiberServerOptions provides options for the Fiber server.
@@ -171,16 +506,52 @@ func RegisterHandlersWithOptions(router fiber.Router, si ServerInterface, option
 		router.Use(fiber.Handler(m))
 	}
 
+	router.Post(options.BaseURL+"/admin/api-key/create", wrapper.CreateApiKey)
+
+	router.Get(options.BaseURL+"/admin/api-key/list", wrapper.ListApiKeys)
+
+	router.Post(options.BaseURL+"/admin/api-key/:uuid/revoke", wrapper.RevokeApiKey)
+
+	router.Post(options.BaseURL+"/admin/appuser/:uuid/revoke-tokens", wrapper.RevokeAppuserTokens)
+
+	router.Post(options.BaseURL+"/admin/token/revoke", wrapper.RevokeToken)
+
 	router.Post(options.BaseURL+"/appuser/create", wrapper.CreateAppuser)
 
 	router.Get(options.BaseURL+"/appuser/list", wrapper.ListAppusers)
 
 	router.Put(options.BaseURL+"/appuser/update", wrapper.UpdateAppuser)
 
+	router.Delete(options.BaseURL+"/appuser/:uuid", wrapper.DeleteAppuser)
+
+	router.Get(options.BaseURL+"/appuser/:uuid", wrapper.GetAppuser)
+
+	router.Patch(options.BaseURL+"/appuser/:uuid", wrapper.PatchAppuser)
+
+	router.Post(options.BaseURL+"/appuser/:uuid/withdraw", wrapper.WithdrawAppuser)
+
+	router.Post(options.BaseURL+"/auth/login", wrapper.Login)
+
+	router.Post(options.BaseURL+"/auth/logout", wrapper.Logout)
+
+	router.Post(options.BaseURL+"/auth/refresh", wrapper.RefreshToken)
+
+	router.Get(options.BaseURL+"/health", wrapper.GetHealth)
+
 	router.Get(options.BaseURL+"/ping", wrapper.GetPing)
 
-	router.Get(options.BaseURL+"/sse/close", wrapper.SseClose)
+	router.Post(options.BaseURL+"/realtime/publish", wrapper.PublishEvent)
+
+	router.Get(options.BaseURL+"/session/list", wrapper.ListSessions)
+
+	router.Post(options.BaseURL+"/session/revoke-others", wrapper.RevokeOtherSessions)
+
+	router.Post(options.BaseURL+"/session/:sessionId/revoke", wrapper.RevokeSession)
 
 	router.Get(options.BaseURL+"/sse/open", wrapper.SseOpen)
 
+	router.Post(options.BaseURL+"/sse/ticket", wrapper.IssueSseTicket)
+
+	router.Get(options.BaseURL+"/ws", wrapper.WsOpen)
+
 }
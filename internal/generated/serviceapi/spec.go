@@ -18,30 +18,117 @@ import (
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xYW28TRxv+K6v5uCDSGq+TkC/xXRoITUtESkC9sFxpsvvaHronZmYBK1hCxKgI6EEq",
-	"aaGNUS6ACilSQaQ0legfYtf/oZrZk50dkwOJKrW9i8fzHuZ53sPjrCLTc3zPBZczVF1FPqbYAQ40+dQk",
-	"LubEcz8LgLaXxJfi3PRcDi4Xf2Lft4kp75SvMM8VZ8xsgYOlA+r5QDkB6c4mDpFGFjRwYHNUrRg6soCZ",
-	"lPjCA6qi/jePot529MstLbz/hxa92Qg3n2nR3UdIRw6+QZzAEVaGjhziJp90xNs+oCoiLocmUNTRReYw",
-	"HGlkIPR+X53syFu5AiZHHXGk9rWu9R88CHtvw193+t2XSEdEfHtVYId05GJHuMlBFYkyj3LiNo8EX4vE",
-	"tA0nxzglbrNWF+lwcBQ3os31cHNLC1++6P/w7OTs8px2UztzdnluDGVvj52gHAxMKW6Lz19C+0OC9m8/",
-	"3TvKfjhI/e1NQAK5dBtDKdOb9f2AAZWg2/aFBqrWVtEJCg1URf8r521STmzKZ11OePsiMN9zGaCO/v7r",
-	"if8Ft+GhTr2jo8GDApcrhPKWhdsK4NZ64Z1u9PPDqPcW6ajhUQdzVEUW5oBE5XMOVFz8omaUZuqrk53S",
-	"SaNWKc3Ub1ZqRmm8PpZ9rlXG6/LSzYmaUamPnVBR0QTXimHZlUf3Vfi6q7KIcS7c722Hz5X3rxPesii+",
-	"XrTpr93tf7WtRT9uhW9u5aYrnmcDdiWDFK4GhIKFqrU4sJ5jlyU/EKNeqKWMifOE8ZSNg5SAsBsqg2Eq",
-	"cexd0QP9x4+iJ99p4fOt6N7T/r2dwW7ZRyntp1FEoc1RwBwSo4twNQDGj7DiDl4x4IphW0OLSEfzA4wc",
-	"toT2WwYq7hUUFqDhHse2Ip3tnhatd6Pedv/ORrKk9l4facjR4WK+rFmupCLqvtKi+xvvXnZP9tduRU+6",
-	"onbWtvoPu2OD5BCXT00i1W5c9CzSICP8/7bx7ve34i3R5vqHhbl8eeFMMYA83YtAeUlF1jlwgRJzNHSm",
-	"Zynq5uNLl5a0ZY55wLToz4fh9xvKjC3M5VbFbjvp/d11uVpoLx3dKDW90oAz2sAmrHaEQwcYy4TIYELp",
-	"F3sBIZ+T+1FBsuS5zSIOPolP4QZ2fFvKDnFvr3jSrBhFLEowA0p4e1mMoDjGlet8NuAtOTsAU6DzaVF8",
-	"8vkltHs/x2dygskRLi3yhFqc+/FaJ8kE5oTLxBeFOMO2JnLTZpcWNAb0mjS9BpTFziunjFOGQMPzwcU+",
-	"QVU0IY/kPmzJdMvJHC6bsr0kSh5T9ECKmfRGpfRasFB1eIyiGDhg/CPPah9ItL1vsitHdWeYJk4DkAdx",
-	"E8jHjRvGkeWQbZeiyLrwqQB5sjJ9ZMF2d7Qi6Dwm9lANytbMqq9WF13IcZOJCk5IRnVhkFFuk5joJhyA",
-	"b7ETZtPdrQ/9NKoV5ubtreinF2KZBwGxxIrLGq8yPnN6Ck5Plk4bE1ZpcqpRKU2vzKyU/m82JozJxgqe",
-	"MiojlGriK0ey0L2j88h2ZZ5J//HX73Z2wm8fjwiX7M3Dhct2exZuUStr8yNCZbrsUMF2y8I8pugNraw1",
-	"sM1gROhMCSqCDwhLddnmRVAu/nLbh5HyF7Wo3+Pu5kzb/oO6OvCtdJAHKi2TF+fmevh6O1E1hT6/LN0c",
-	"71wfmqn/4lE+u3D+kKSnikY5wqU2SBTOMLnngC8ReX5sIEsFpnisyEejORr5y2KZJZ/FGJRN24u17IHW",
-	"0zKDOWl4jE/bB6V/7/BIEWUMBgAVKvAweF4Qdv/BmcEpG1WIbZXkOe+Z2NbOwDWwPd8RaeoooHai5avl",
-	"si0utDzGq9PGtFEWsnygvXe7S7o0/Sdp+ntr+FI+pJN76Yzo1Dt/BQAA//+J/lttyxYAAA==",
+	"H4sIAAAAAAAC/+x9fXMTybX3V+maJ39IFUmWwRBQ1VMpwkviBBaetffZ1MW+67HUtmeRZ5SZEeAQV2mx",
+	"2KvF3ot31woCJEfcazBsnIpiBBEVcj/M/VPT+g63TnfPe48ke20Me/nL1ky/9+lzfn3e5paU1RYLmopV",
+	"05Ayt6SCrMuL2MQ6/zWvqLKpaOr/K2J96Qq8hOdZTTWxasK/cqGQV7K0zMjnhqbCMyO7gBdl2oCuFbBu",
+	"Kpg2ly3qhqbDfzlsZHWlALWkjET+sUXKddT79xpptMl2NVaQ5zGy1kpktYnI7R3y8Hk8hcjun50iJevu",
+	"BrJeV5C10yGb6+R+O4FIo917VIYXpPGNtfqSNGpIxTfNs6zXhGQuFbCUkQxTV9R5aTkh5ZVFxWTDmZOL",
+	"eVPKjKYTgbE5PSJr9TUir+pW8wkilZqUkBblm8picRFqpRPSoqLyX05PimrieaxDVzAjf0+RHUn921p2",
+	"Hmmzn+OsKS0vL0e1VUW9tTWr8cb6a6dXbkkJSYG3v4O9lBKSKi9CM+4mw0ANbBiKpo7nrsjmgrPhgf0q",
+	"d8idGho/h2IXFcOcYFUMBCuu5OJ2PwXZXHC7cRqWEpKOf1dUdJyTMqZexAkPwSzKNy9idd5ckDKjx07R",
+	"lXB+h3YQhqvppqLOHwh55hTdCE+WdXZ1GmZl4kVBCdKsWs0dZLWe9/74JHZm4iz6Azp3fuJsXERz/IGs",
+	"6/IS/L6Gl35Ip73bW4N7GYZk7PYG0wtfctqNVlCy/rX3t9p9uWPdu9OrNhEtimLQBrJq63BM4f/MNbyU",
+	"QKRWQUUD65lbxaKSW04guVCA36liISebOAen//6O9acd1G3VredtZLVq1ouXUkLCN+XFQh5LmatSoI40",
+	"DW8LeS2HbTITzYaOqy9JOhtQkE0T69DGv149k/wXOfn7dPL0tPtv6rPk9K104uTx5Vgm8HQ0cXJsOf7z",
+	"n4j2alG+Oc66GD1JCd7+FSYWw1yCuUpzmr4Iv2G1+p3T+xu91VZvtYU++WT8nPhYQhN9pw99yaZbMngG",
+	"l+3CdI3OFJTf4CX4T87nL89Jmau3pJ/oeE7KSP9nxBU1I7zKyHnVVMylj7FR0FQDS8uJ/sVZ8+PqnCYt",
+	"Ty8nJM/v0HHGNwuKjo0zZnhhrO016z/XEGm8Iat1FCuqyk20qOTzChxZZ8KKap4ck0Tc/BpeCjd6DS8h",
+	"8mjd2umkkNWqdztf2YLo/jop163tNSDb3oOaUBTJhvmJgXPi0VZA4m1/xWWhYNwJZL0qI2v1GamXh5wD",
+	"23/hJBpt62lZNErthop1cSXryRs+vF61RoVwuW69LpO7W2gEWatta/U12VwXNVrQ8Zxys996IlLdtF6V",
+	"YIox4CivyoAIRG3p+Lp2TbyKvXvr3U5r/3tuZLUCFjDi3v0KefjcWq8jWgJZ3z+3Hje8jHswd3ZP31W2",
+	"L/ZSO6vj9D8d4uX2KQBRLD4JMn1v+JjZ4FM2cKR2s/2H5JztoXnCL7GKdSXrYwoBSS2b8nCTcBZFIASn",
+	"gzIQmkXdVgn56yLS6KDwoPgcj3Z++5lX9HyoAD1E5k3b93Jv90GIaGcV3VzIyQJGS1Ya1p0yebRBGm+8",
+	"ZxfEvpTwiWkQ0LfGlpOx9NXR5OnpP4xeTSePTced31dHj03TQn84fjU9Oh0XCuh5rOZEbI+U/2a9EPJJ",
+	"MW+N5qu6lhexFnjM+QmKMVmCSBOYXwJZL0rd3X+SRgldkc3sAl/K+B74TkK6jnVD0dRwx6RSI80qsnYr",
+	"vVoHxc5PyvOIPCihbusB9NhtfRtPoU8ozuIdI3J/HY3PJS/BYCitkftfkvobAGuk0YYq8OtxHZFXz617",
+	"a6j3oEoaneFY7w3FXMjp8g0B812p9P6tTcHhq5Jbd1bT8lhWvXXVM2Z09f3JBDHjdujWIRzPBMSskq6g",
+	"l33v5fj5eGyYlXBULBJcD2pkcx1ZT3fI3a3e3Y6XdIY4xsNcM6b90zs6Vulf4T3yTF/lAczziKe4r6lF",
+	"TemsjukBZ1Lud0VsmAeEslMe7sBwGVwRrepa9/UaqFpIvdl7+OxgcCyKkVolhVRlfsHMLyXxzYKmm3Gm",
+	"vrFVCyfHBmgaDgb67km5MQhxwopFIc69Tc29gB4/tjdw2geT2sTDT0UE9exbyO9dSGMVFGpXpUtSQrog",
+	"TQuayGvzikAYWo/r3b934IyAwqsgG8YNTc9RedirPuv+o4bIdok0q7D5z9toRC6aCyO0LcRFnttCt1Wy",
+	"7j6J750W9ooo7GEKpvO6bLVKTLR7F9ap4RvbsRMnfWM7FewrId3QFRNfVvNLTHcwtFgUUY1ApIVoxqPT",
+	"DU9udYs01lw1MeKq5VhQxYzIaj2B3Ju1U4MClvtfksaaWH2nmXJesBHtBiLVMqig79S5eniw4taeb/Rc",
+	"2SkS3mfJSoOU/wbz6LbKsd5KiWwCy+mt7PQ2htUBXNJyypwS0f7LevfvbxCHgj+oG6p9CnVAn4p0ul7q",
+	"oYVElBKUV2FjA1X8BTv91eTkFTRhymbRQOSfG9Z3deGIbZErq0tchAcp4ZZAzN5MzmtJT2P6nJzFt5Yp",
+	"j8WGIc8LBmS/GLQQdDpuO6Il+RWW8+ZCeCV0nFOGv/x/DKV5U8siFSSsXXge2rUEyuF5Xc7hHIopanIR",
+	"L2r6EpqT8/lZOXvN0V1tfRNPoJx2Q0UxmGrWRKRZIptPyJdrdhnrVaXbKsU9TFu7JiUku3n4V7uhStOD",
+	"1oyPNcFXIHrNjgq12cu8F9DG6kRhtosgdyLF7RASbu+i6bBEzeig7WWz8QxAtMEXtXmtaEauiI7ndGws",
+	"TGrXsGBhuHx3ISovjkwoj2L8PsyFPVXzIl0zqbkJgUqQlbO+f2Z9s86biQuPemjc3lv9+wGfDkPpIecW",
+	"FRVR1TnVd3gAlAjsHj82gIKC1pYgd9u/pkG4h5o6H960gsKeOqYrqaBRINWf2mm16YhejoqBXdH4SIdm",
+	"X1Ajinld0bXZPBYYsj6+cBadHjvxM8RLoHPYlJW8gWJe+26Bvfwp2HnjUiIwHTEe6L5oUnhzfx2siwwR",
+	"oFjvi7bVeAOA8I+d3t0OGFe6nVaU0WF/YCFoqoMBeO8UzbK1usMvlNbXLYCW5Ra/zI6lT1Nje69WJps7",
+	"yLHzxaW+ICRHV01A2/7pgirPetEm93fIVhkAICKbFXav3bK+vyNaAkU1TFnNis7/ww2y+xfU3f2n9bgu",
+	"qhoFKIZEaqZi5gfgPGun0325E20m93hlSPKsVjQzs3lZZYjDJ852OqRZp8qJWhV98vG4qMnripan5Ci6",
+	"uFfLQF7MTIe6uyXytIHI6lZv7dmwyrf/bzc/0DpDX9rrk3CxED0HQj5SnM0rxsL561iNlpc2rYvM/NQR",
+	"Z727C8y6bd1+g349cfmj/hSJr3M/DX97ExPnKX3T1649crABPmNb4H8ivrwVlGy4N5FPAooFfBLiQ/W/",
+	"DweA4LZxfwS2MqJ98gL08P7MCrgnVECwc7OygZGDx8KHaU5W8kVdJJXJ/Rb58mtOrKj38E/AFmJkc4fc",
+	"b3V3W3A/tO6VUe/eTq/6LC5s3FCEDAIIE1PRYLU2rNL6D7DQQkvhDooFFKNLEO9/Q4m8liSQzcT+1rJe",
+	"+e4lxcJQ95KExNoUrOvmE1Jtwq0IuSOiZpJmufeg1qvWqBajepfyX/ei1AeGeKkpNyvZ6+KMwd4Jz3aL",
+	"yYxC3X0iZ0ezYqvAqEOE1XoEeNmHoqW9gX5ft+JxgwsAfb8fpXXvy2bvixZlPjcLQaW1V8/X/XsHqJ40",
+	"15nSGkAq9zFg6JWL0W67NBz5fm4qUV4LcPVwB/a5qSAQ2c0K2SzvcfmgE9GqcR++8FJlB2ug9ntes0Vd",
+	"F7J/dtx61bpn0tznsI+lL4evK0IEQmuSRhnuEdZ3TX6aodFPDKwnz8zDIARntp9p4+sNV+XPTyi3ZzCr",
+	"h7XOLAFC24ddkekaW2TzibVe4yTEF7X3qIwmzk9MjF/+6LNLZ3772cXxC+cnxy+BWCwjq+z02Vgbbq2V",
+	"XF83TnedDSWX8FhnPjcVIeZVCn39lBi/HL8S5eo0gbH6tlydAieAOrHZpJfw0LdvZH3OSLSfDXdxHV7X",
+	"xlsciOWchgcM66iugcGV2dONMFA56nI4YeBJJXsNm2/V0890uvS3O2IYeEQrYJXiVFYK2a6lAxAea9LL",
+	"X4Tbas/3yDbVWfC9baddLWojqWiOcFTLZrFhRECKM0VzQdOV3zPtWu/+lvX1BshYm5020a8/nezDx8cF",
+	"bbIOuZqOWZ1Rt9MCUwdpV8Q4liOQ89Gt+rWEe2s2UhNZB+BOJQTjjEwTluL2RrtLroQkKxWnd4q/ui/X",
+	"yW7bWn8mvhddw+qkcxf29/sXsvIF+gWWdax7gC9/MFAF791Rb0feXQnMXbDCovPBEd7RnA2Xhvd0Npxq",
+	"UWfDvd6HzsacgvMiIV4vk9c1pjii2gWmIZrVckspuMwmGFdKuVEeQnkeaZ/iNz4gunpzIG9jg+xnpqIh",
+	"JtmirphLE7CYXg9VOOHhERhYB2CXNLUk/xf1ah3yqg6n4MyVcUSv6yNUVTwiF5TkNbw0wsS6fRzY7cMJ",
+	"TFnAMvPL4j7wv02euTKeBG9KVw47LrCf3zDtcc1Sor9gSw3GbvyDZc8omVB4ap8b3uyCaRagUcMrzPwt",
+	"UP3LhFbUsxgBhGSMDnxMuFaFKuQYQPtrCc42lUVcBDkTphctsr1N/1Rq1vd3PIIKQCQlC3rVhHZXyhHR",
+	"Ho60CizMMtX6MSbOdXDSJQhbkvMIVNV0Z2C/6PQdT0NpNJVOpWEJQHjKBUXKSMfpI6peWaDUINxLeFHQ",
+	"DHOfBMJIIGV7lNNHFKC3fV76yMNoHW99OIb06IznpIzPLYpHTmDD/IWWW9pT7FE/9iLyvFr2nzXqagEP",
+	"GPOg63YsPXpgQwg4Nwsihtggc+zqxfWnkZ17dfPDD8I2CAh6P6/rmu5jJ5T1O6f16jTwYVOeN6gYAoKS",
+	"qImem52cZ9BEgODyCiOzedH55JqM9bpLWtxYxe7q8AaeMlVYIkhwjKg8t0bmaOInMcDCZ7h3fWiP0we8",
+	"x34H0vBKX/7Nj3CLmVJ3hMWLRLMWe4vZ1qaQf4upnq7RIU8bwDVstQJTZD6uo7H0aGhnmXbKYR7ecNsI",
+	"4OIWGfGHey1Ph0hjLDyDjzR0lu/XckIaS4+9zR38SDPRBa2o/riYBLXJ+ykoSZGtEU1IDLS7FhKyXep2",
+	"Ktzd1WUpvvvISMDdgKp+vK4EvWqt+6LpqMZu/wXMU7Fuqwp/uVNHtUwercQj6ZDOZZIN/gM5vofkSElj",
+	"IB9j+j0EOsfbNcCSoEMGHsU11jFSb0Js+7qtcqVaNrK6Ra+tnVYUAbk3toMHQQJF/lAYaCDVvf87z1nQ",
+	"IGBse5hEIVjayiFDWJ8D01D7d5D4xh+TEQluxkZPvU1quABeGO8oIdoBQxnq1i356ZK9GoIy+yLoKLJk",
+	"qJc2IBBGEfIU8bB015tq9NjpEyfxibHkifTxXHLs5Nxo8tTs6dnkz7Jzx9Njc7PyyfRoxGWXt+Wuckjb",
+	"ET0Ox0vBHUnvwdfdTse69yCiO/pnv905jnlOd5fQCLoQ0ZUTiravzoLOb26fcITRCNjQDRzRteNTJ+jc",
+	"Y7ceCDfCqT6GqCTMYLM8ffhMZ7hb1QfGE2Y8OpZz0jTsrVcrGHq/L77EcoNQiVnsh9IRpEN50ebRD6kp",
+	"1QltZeq42C/PT3rSDEGAbNx2IQLVK7UKcaUX1TFxTz37UffFG2StbllP2mBkpe58D0p2Icedjyr9qL5g",
+	"Sg1xS1/47SEJcTeq8B2U2wmux6Xdww5ExTEDqnXW1LMxfbnh8hFfT8bSp99m32c1dS6vZE0Us0mUU7Mv",
+	"pjuCip+WE9S9jAaBh31Sj4DPnRm/+F4DLB/TYpoGRt15bOK+fIsqAULM4hyt6DKLA77oH9zRD9nEIiXn",
+	"UZ7ND1L7h18XEuLLgYeUH7d6D9dCpPxLbL4HdHxwIszJ4WVz3Vgg8Uaj5jJp1x8i/m5Ltw8n6CBxL43C",
+	"M7MLQyBankeLh/XGIE5s8yV1taTuA9QmWm+S7VJ8ONTbBxb8AHDrjX47kGN+8NhYFKD3ASf/78HJ7ycw",
+	"ft+hhAAYj3iDNgdY32xNVszQ5kzE4HQ8xS1o1FXm6VfgKOo4cYN9jTnxcwc+6jCyXbJusyCAVh0MwJS1",
+	"Ah2Qxkvr+zu9BxvM087P1D7l4/zRwJcPIOI9PDtOdpro40JfoxHk5LqxHgdM08Dg/MZpZsMO6/R5jP5h",
+	"SGBfroO3LHr93qd9jsfo26STcfW6nFdyKKvjHFZNRc4bbBTH3+YobD6n2mmcj/zUOEcBTov/HGhFM/og",
+	"+D0rrNa9ANFzt6ApNeBHzhId8igmJ1rMPTocCjDbuhvdBA4eXs+OBPI6RlP/Dx4dTl1B23bDc/Kikl9C",
+	"1j27emhEDTcuaZ1H9vjd6WnKo7a/P5Z2ATqivfAsF+BF6kwkOG4BlGcJOA6PBXiyeyxzJvBjcAdI3PLx",
+	"8ADh8n2Kptyw5xCEBPR3L/JGCqSoPfOvHSe6oh4sDSS1ugVgidS2GBbyJkNp1Dx9+7yWBK4kAe//w/Al",
+	"8QWzfhAWrrBIIBaJkQNuQ7NnI01HOi4aOOff8w+iRCxKFpxYfLFG80HHelXyBW+zkPmVL3or9RQKhpvH",
+	"bGctbzAx1bPQ8HQ4l8/b6ET6uEg1yvMCHCIxBzJmCamZB9zTq7EoFdjWN3wWLCsFLWdH0dNr8Yn08bc4",
+	"YObDS+/o1TJ5uEG9ee3t8Gb3bDrBF421d4YSWWIgSol2aiEhHcJLxFMNhejmiqLOHybV+JIUCaYF40K6",
+	"U+DdW1odqEhZxCMFliYlWvSyL52AAHTSosQmJs4n0Kd4dkKDaJoEFYjfNZGdNiduewU32tbuFs041Kr3",
+	"/vhVCgHKe1VyM8pCSIudFYsZaO2BZfjAeFJY6J97r4Mml2VSgXM2k81rBp6x+YgzRqhgmDqWF5nIXv0z",
+	"aayJVLOeNDGHJK1FmWjessge2g75rqLHgBEhSCV+FYFh4D7OMzz6vL9Tn5uzwXV1b9T4Fcd6Uba91J10",
+	"9jy9KA/zr9TFUTD2t6UOkzeJAujf3zgYvluBveNhCpq5YH/aTewnznXUzrWONOvkQccNbwltsFuUg3x2",
+	"WeahCXZeiXI09odxXYZhRW/1++/RLd6UW8430QZHIvVdfo9jv2/5aY4avkG10GagGLXSdfghHSpihG/S",
+	"nnXXgs/KfQgYOVwi4wkqIjm2jVPKyP5KG01Zw2J2kwbgBYoaDA4LUiiQJ5Dm7PriLmTFul2jEGW3DM/I",
+	"f9yhmqZARn+yXUpNqQ7Egb5mlFxmJoFmaD/0P7CWZWZQDFLHxZ3o5Nttqg0bPUHaFWu7Yq1uoZkMDTOe",
+	"QVltcRHGakdIr5RTU6oX5LDe4G3rkf1FEnes9C7mAp7UlOrPAEULUXsPn+cClnVzFssmzM45U21PaGB3",
+	"d4XrUthHTiAX0PM2Co0JxWZuTYFkNjR1SsqgKZYfwb6PT0nLM4kpVVyGX9WhTNyZOl0mB7nZ2hk6Q/59",
+	"BTRzUTbMJEVWyfFzM7Zh3xt0Tme8uW7de8BnwVY1zp5X+BIy6uEsAzJd3X5GqmWeKXJKBc5jfbtFWZQ9",
+	"3xivwzaQA4AT6XS35UvF7q4PJEEaTVuvynE6w2dvSLNk7zHq1apktUFXfrVkPd1hvgbp1JQqShRio+UZ",
+	"Fs8+4wl/Z7e/FGKoGhok1bK1WqGEsrlOVpswixlPLsAZe/bba/zs8GwcKDaWPh4XgOYJA18u4L2zzeAH",
+	"FsNO+r79jE4WAAReXQN/j4qTBIDtrnfraUVGNjRX1hZUiqd4s5QAHrZIuRnh6Z+XDZM2N+6Pogh+kemn",
+	"SfZHlA1xsFXTxDfNEcoxkowx+Vmt4PuE/hWjVTlPYzIl/TZ5OicdRo6B3aMkTfWpT2pHoG1zv9bJyQaG",
+	"SMf7Tt90PMk0roavNY4wdFM5iRHWjCMyf86K/t9UKjWDYjMjN4wZalyhGu61OPOy+67jT68Bd/zPJsfP",
+	"/ub85GeTkxcTntx1dhIq+IbCF6EEbnFPYg6epWJK9SUNQsCLqxXoy3taoR5j1MhJxElZMWf833XYPm5W",
+	"qPR0E9MBY1upsLSMj4Br+0SFgH2NG0YRu/mgDvMuFsp69R7fxBzyu2HsFYU5uqIUoslw3Y+vLRRnkU+s",
+	"cmhDr1vBvNXPNyBF5XYpKBE2KwzrcD2UW+7uBs3ai4DJojmQQ6kpNckBE/rvL78N9pFBAFCA6TJ4Qrnr",
+	"lJRAU5KSY3/pFNm/nreA9DjEQYE2dFzIL7FSOp4LFA/2B1sSKMu0yuz/rJbD7D+ekwgagSkF1wrmxqYZ",
+	"nNJUMZ0+nl2Q1Vwe6/QH9nTolqDDpszBurdmNbcge3a39a23BptFBt1aXp5J0YkbxVkgh1kMMyuq7k8U",
+	"Y2AY3eIraEDFq9MU8N3d4IeV2dlsErJaG93/6tCbH4MlVNPe4SV4WtJGGbEFVtl0obHjaRdZUz0xbc2B",
+	"lCfhNedCFB3ttLu7K8ASgYFABoPv/+Hms4wC1J7cmJF3BtoGBcqM+lBsNJ0+lUCj6fRoPDAqpwOrAx+t",
+	"BmjSLA2XMmnGIxFmUN/MSLfbdJ7rz8CofogQ8VPjYBBiEECNpkfDXGfihmJmF2Cjr+iaqWU121HkCKDQ",
+	"u416xo6dfKsGT5vvo2KB2sOctDKc5mrvJxS7YYAopLVBvSAK6b6oZeU8Ooev47xWWGTWhaKe51nTMiMj",
+	"eSiwoBlm5lT6VBpS+UieLoLNcYsWv5oU7A8x+Au5rpi8nGtmDha13RPsgkVzQVDK1Y/xcoaT4jXUN1Wx",
+	"uw3Sn6IWg/oYT+MGFtRwaMgteMOAj3H+zwByzzSc7YMAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -8,15 +8,130 @@ import (
 )
 
 const (
-	JwtAuthScopes = "jwtAuth.Scopes"
+	ApiKeyAuthScopes = "apiKeyAuth.Scopes"
+	JwtAuthScopes    = "jwtAuth.Scopes"
+	SseTicketScopes  = "sseTicket.Scopes"
 )
 
 // Defines values for CreateAppuserRequestGender.
 const (
-	F CreateAppuserRequestGender = "F"
-	M CreateAppuserRequestGender = "M"
+	CreateAppuserRequestGenderF CreateAppuserRequestGender = "F"
+	CreateAppuserRequestGenderM CreateAppuserRequestGender = "M"
 )
 
+// Defines values for HealthStatus.
+const (
+	HealthStatusDegraded HealthStatus = "degraded"
+	HealthStatusDown     HealthStatus = "down"
+	HealthStatusOk       HealthStatus = "ok"
+)
+
+// Defines values for PatchAppuserRequestGender.
+const (
+	PatchAppuserRequestGenderF PatchAppuserRequestGender = "F"
+	PatchAppuserRequestGenderM PatchAppuserRequestGender = "M"
+)
+
+// Defines values for RedisHealthState.
+const (
+	RedisHealthStateDegraded RedisHealthState = "degraded"
+	RedisHealthStateDown     RedisHealthState = "down"
+	RedisHealthStateUp       RedisHealthState = "up"
+)
+
+// Defines values for TokenInfoTokenType.
+const (
+	Bearer TokenInfoTokenType = "Bearer"
+)
+
+// ApiKey defines model for ApiKey.
+type ApiKey struct {
+	// CreatedAt 생성 시간(타임스탬프)
+	CreatedAt *int64 `json:"CreatedAt,omitempty"`
+
+	// ModifiedAt 최근 수정 시간(타임스탬프)
+	ModifiedAt *int64 `json:"ModifiedAt,omitempty"`
+
+	// UUID UUID
+	UUID string `json:"UUID"`
+
+	// ExpiresAt 만료 일시 (unix milli)
+	ExpiresAt *int64 `json:"expiresAt,omitempty"`
+
+	// Key key 원문. 발급 응답에서만 반환
+	Key *string `json:"key,omitempty"`
+
+	// LastUsedAt 마지막 사용 일시 (unix milli, 분 단위)
+	LastUsedAt *int64 `json:"lastUsedAt,omitempty"`
+
+	// Name key 이름
+	Name string `json:"name"`
+
+	// Owner key 를 사용하는 서비스 / 담당자
+	Owner string `json:"owner"`
+
+	// Prefix key 원문 앞부분 (구분용)
+	Prefix string `json:"prefix"`
+
+	// RevokedAt 폐기 일시 (unix milli)
+	RevokedAt *int64 `json:"revokedAt,omitempty"`
+
+	// Scopes 허용된 scope 목록
+	Scopes []string `json:"scopes"`
+}
+
+// ApiKeyInfo defines model for ApiKeyInfo.
+type ApiKeyInfo struct {
+	// ExpiresAt 만료 일시 (unix milli)
+	ExpiresAt *int64 `json:"expiresAt,omitempty"`
+
+	// Key key 원문. 발급 응답에서만 반환
+	Key *string `json:"key,omitempty"`
+
+	// LastUsedAt 마지막 사용 일시 (unix milli, 분 단위)
+	LastUsedAt *int64 `json:"lastUsedAt,omitempty"`
+
+	// Name key 이름
+	Name string `json:"name"`
+
+	// Owner key 를 사용하는 서비스 / 담당자
+	Owner string `json:"owner"`
+
+	// Prefix key 원문 앞부분 (구분용)
+	Prefix string `json:"prefix"`
+
+	// RevokedAt 폐기 일시 (unix milli)
+	RevokedAt *int64 `json:"revokedAt,omitempty"`
+
+	// Scopes 허용된 scope 목록
+	Scopes []string `json:"scopes"`
+}
+
+// ApiKeyListInfo defines model for ApiKeyListInfo.
+type ApiKeyListInfo struct {
+	ApiKeys []ApiKey `json:"apiKeys"`
+}
+
+// ApiKeyListResponse defines model for ApiKeyListResponse.
+type ApiKeyListResponse struct {
+	// Code HTTP Status 코드
+	Code int             `json:"code"`
+	Data *ApiKeyListInfo `json:"data,omitempty"`
+
+	// Message message
+	Message string `json:"message"`
+}
+
+// ApiKeyResponse defines model for ApiKeyResponse.
+type ApiKeyResponse struct {
+	// Code HTTP Status 코드
+	Code int     `json:"code"`
+	Data *ApiKey `json:"data,omitempty"`
+
+	// Message message
+	Message string `json:"message"`
+}
+
 // Appuser defines model for Appuser.
 type Appuser struct {
 	// CreatedAt 생성 시간(타임스탬프)
@@ -37,8 +152,17 @@ type Appuser struct {
 	// Name 이름
 	Name string `json:"name"`
 
+	// Roles role 목록 (응답 전용, 변경은 PatchAppuser)
+	Roles *[]string `json:"roles,omitempty"`
+
+	// Version 수정 번호 (ETag 와 같은 값). UpdateAppuser 에 If-Match 가 없으면 이 값으로 충돌 확인
+	Version *int64 `json:"version,omitempty"`
+
 	// Withdraw 탈퇴 여부
 	Withdraw bool `json:"withdraw"`
+
+	// WithdrawnAt 탈퇴 일시 (unix milli)
+	WithdrawnAt *int64 `json:"withdrawnAt,omitempty"`
 }
 
 // AppuserInfo defines model for AppuserInfo.
@@ -52,8 +176,17 @@ type AppuserInfo struct {
 	// Name 이름
 	Name string `json:"name"`
 
+	// Roles role 목록 (응답 전용, 변경은 PatchAppuser)
+	Roles *[]string `json:"roles,omitempty"`
+
+	// Version 수정 번호 (ETag 와 같은 값). UpdateAppuser 에 If-Match 가 없으면 이 값으로 충돌 확인
+	Version *int64 `json:"version,omitempty"`
+
 	// Withdraw 탈퇴 여부
 	Withdraw bool `json:"withdraw"`
+
+	// WithdrawnAt 탈퇴 일시 (unix milli)
+	WithdrawnAt *int64 `json:"withdrawnAt,omitempty"`
 }
 
 // AppuserListInfo defines model for AppuserListInfo.
@@ -61,10 +194,48 @@ type AppuserListInfo struct {
 	// Appusers 환자 리스트
 	Appusers *[]Appuser `json:"appusers,omitempty"`
 
+	// NextCursor 다음 페이지 커서 (커서 페이징 시, 마지막 페이지면 없음)
+	NextCursor *string `json:"nextCursor,omitempty"`
+
 	// Total 총 아이템 수
 	Total *int `json:"total,omitempty"`
 }
 
+// AppuserListResponse defines model for AppuserListResponse.
+type AppuserListResponse struct {
+	// Code HTTP Status 코드
+	Code int              `json:"code"`
+	Data *AppuserListInfo `json:"data,omitempty"`
+
+	// Message message
+	Message string `json:"message"`
+}
+
+// AppuserResponse defines model for AppuserResponse.
+type AppuserResponse struct {
+	// Code HTTP Status 코드
+	Code int      `json:"code"`
+	Data *Appuser `json:"data,omitempty"`
+
+	// Message message
+	Message string `json:"message"`
+}
+
+// CreateApiKeyRequest defines model for CreateApiKeyRequest.
+type CreateApiKeyRequest struct {
+	// ExpiresAt 만료 일시 (unix milli). 없으면 폐기할 때까지 유효
+	ExpiresAt *int64 `json:"expiresAt,omitempty"`
+
+	// Name key 이름 (예. nightly-export)
+	Name string `json:"name"`
+
+	// Owner key 를 사용하는 서비스 / 담당자
+	Owner string `json:"owner"`
+
+	// Scopes 허용할 scope 목록
+	Scopes []string `json:"scopes"`
+}
+
 // CreateAppuserRequest defines model for CreateAppuserRequest.
 type CreateAppuserRequest struct {
 	// Birthday 생년월일
@@ -73,8 +244,14 @@ type CreateAppuserRequest struct {
 	// Gender 성별
 	Gender CreateAppuserRequestGender `json:"gender"`
 
+	// Login 로그인 ID (password 와 함께 지정하면 /auth/login 으로 로그인 가능)
+	Login *string `json:"login,omitempty"`
+
 	// Name 이름
 	Name string `json:"name"`
+
+	// Password 비밀번호
+	Password *string `json:"password,omitempty"`
 }
 
 // CreateAppuserRequestGender 성별
@@ -82,6 +259,9 @@ type CreateAppuserRequestGender string
 
 // EntityListResponse defines model for EntityListResponse.
 type EntityListResponse struct {
+	// NextCursor 다음 페이지 커서 (커서 페이징 시, 마지막 페이지면 없음)
+	NextCursor *string `json:"nextCursor,omitempty"`
+
 	// Total 총 아이템 수
 	Total *int `json:"total,omitempty"`
 }
@@ -108,13 +288,256 @@ type GenericResponse struct {
 	Message string `json:"message"`
 }
 
+// Health defines model for Health.
+type Health struct {
+	Redis []RedisHealth `json:"redis"`
+
+	// Status ok, degraded (in-memory fallback 사용 중), down (strict 저장소 사용 불가)
+	Status HealthStatus `json:"status"`
+}
+
+// HealthStatus ok, degraded (in-memory fallback 사용 중), down (strict 저장소 사용 불가)
+type HealthStatus string
+
+// HealthResponse defines model for HealthResponse.
+type HealthResponse struct {
+	// Code HTTP Status 코드
+	Code int     `json:"code"`
+	Data *Health `json:"data,omitempty"`
+
+	// Message message
+	Message string `json:"message"`
+}
+
+// LoginRequest defines model for LoginRequest.
+type LoginRequest struct {
+	// Login 로그인 ID
+	Login string `json:"login"`
+
+	// Password 비밀번호
+	Password string `json:"password"`
+}
+
+// LogoutRequest defines model for LogoutRequest.
+type LogoutRequest struct {
+	// RefreshToken 함께 폐기할 refresh token (같은 login 에서 rotation 된 token 모두 폐기)
+	RefreshToken *string `json:"refreshToken,omitempty"`
+}
+
+// PatchAppuserRequest defines model for PatchAppuserRequest.
+type PatchAppuserRequest struct {
+	// Birthday 생년월일
+	Birthday *openapi_types.Date `json:"birthday,omitempty"`
+
+	// Gender 성별
+	Gender *PatchAppuserRequestGender `json:"gender,omitempty"`
+
+	// Name 이름
+	Name *string `json:"name,omitempty"`
+
+	// Roles role 목록 (admin 만 변경 가능)
+	Roles *[]string `json:"roles,omitempty"`
+
+	// Withdraw 탈퇴 여부
+	Withdraw *bool `json:"withdraw,omitempty"`
+}
+
+// PatchAppuserRequestGender 성별
+type PatchAppuserRequestGender string
+
 // Pong defines model for Pong.
 type Pong struct {
 	Ping string `json:"ping"`
 }
 
+// PongResponse defines model for PongResponse.
+type PongResponse struct {
+	// Code HTTP Status 코드
+	Code int   `json:"code"`
+	Data *Pong `json:"data,omitempty"`
+
+	// Message message
+	Message string `json:"message"`
+}
+
+// Problem RFC 9457 Problem Details (application/problem+json)
+type Problem struct {
+	// Code 고정 에러 코드 (클라이언트 분기용)
+	Code string `json:"code"`
+
+	// Data 에러와 함께 전달하는 데이터 (예. 409 의 현재 엔티티)
+	Data *interface{} `json:"data,omitempty"`
+
+	// Detail 클라이언트에 보여줄 수 있는 설명
+	Detail *string `json:"detail,omitempty"`
+
+	// Instance 요청 경로
+	Instance *string `json:"instance,omitempty"`
+
+	// Status HTTP Status 코드
+	Status int `json:"status"`
+
+	// Title HTTP Status 문구
+	Title string `json:"title"`
+
+	// Type 문제 유형 URI
+	Type string `json:"type"`
+
+	// Violations 필드 단위 검증 실패
+	Violations *[]Violation `json:"violations,omitempty"`
+}
+
+// PublishEventRequest defines model for PublishEventRequest.
+type PublishEventRequest struct {
+	// Data 구독자에게 보낼 JSON
+	Data *interface{} `json:"data,omitempty"`
+
+	// Event SSE 의 event 이름
+	Event string `json:"event"`
+
+	// Topic name 또는 name:key (예 user:{uuid})
+	Topic string `json:"topic"`
+}
+
+// RedisHealth defines model for RedisHealth.
+type RedisHealth struct {
+	// Db Redis database 번호
+	Db int `json:"db"`
+
+	// Failures 연속 실패 횟수 (재연결 시도 포함)
+	Failures int `json:"failures"`
+
+	// Since state 가 바뀐 일시 (unix milli)
+	Since int64 `json:"since"`
+
+	// State up (Redis), degraded (in-memory fallback), down (strict 저장소, 요청 거부)
+	State RedisHealthState `json:"state"`
+
+	// Strict 장애 중 in-memory 로 전환하지 않는 저장소 여부
+	Strict bool `json:"strict"`
+}
+
+// RedisHealthState up (Redis), degraded (in-memory fallback), down (strict 저장소, 요청 거부)
+type RedisHealthState string
+
+// RefreshRequest defines model for RefreshRequest.
+type RefreshRequest struct {
+	// RefreshToken 마지막으로 발급받은 refresh token
+	RefreshToken string `json:"refreshToken"`
+}
+
+// RevokeTokenRequest defines model for RevokeTokenRequest.
+type RevokeTokenRequest struct {
+	// ExpiresAt 토큰의 exp (unix milli). 지정하면 그 시점까지만 폐기 목록에 보관
+	ExpiresAt *int64 `json:"expiresAt,omitempty"`
+
+	// Jti 폐기할 토큰의 jti 클레임
+	Jti string `json:"jti"`
+}
+
+// Session defines model for Session.
+type Session struct {
+	// CreatedAt 생성 일시 (unix milli)
+	CreatedAt int64 `json:"createdAt"`
+
+	// Current 요청한 토큰의 세션 여부
+	Current bool `json:"current"`
+
+	// Device 세션을 만든 요청의 User-Agent
+	Device *string `json:"device,omitempty"`
+
+	// ExpiresAt 더 사용하지 않으면 만료되는 일시 (unix milli). 사용하면 연장되지만 생성 후 SESSION_MAX_LIFETIME 을 넘지 않음
+	ExpiresAt *int64 `json:"expiresAt,omitempty"`
+
+	// Id 세션 ID (토큰의 sid, 없으면 jti)
+	Id string `json:"id"`
+
+	// Ip 마지막 요청 IP
+	Ip *string `json:"ip,omitempty"`
+
+	// LastSeenAt 마지막 사용 일시 (unix milli, 분 단위)
+	LastSeenAt int64 `json:"lastSeenAt"`
+}
+
+// SessionListInfo defines model for SessionListInfo.
+type SessionListInfo struct {
+	Sessions []Session `json:"sessions"`
+}
+
+// SessionListResponse defines model for SessionListResponse.
+type SessionListResponse struct {
+	// Code HTTP Status 코드
+	Code int              `json:"code"`
+	Data *SessionListInfo `json:"data,omitempty"`
+
+	// Message message
+	Message string `json:"message"`
+}
+
+// SseTicket defines model for SseTicket.
+type SseTicket struct {
+	// ExpiresAt 만료 일시 (unix milli)
+	ExpiresAt int64 `json:"expiresAt"`
+
+	// Ticket /sse/open 의 ticket query
+	Ticket string `json:"ticket"`
+}
+
+// SseTicketResponse defines model for SseTicketResponse.
+type SseTicketResponse struct {
+	// Code HTTP Status 코드
+	Code int        `json:"code"`
+	Data *SseTicket `json:"data,omitempty"`
+
+	// Message message
+	Message string `json:"message"`
+}
+
+// TokenInfo defines model for TokenInfo.
+type TokenInfo struct {
+	// AccessToken Authorization 헤더에 사용할 JWT
+	AccessToken string `json:"accessToken"`
+
+	// ExpiresIn access token 유효 기간(초)
+	ExpiresIn int `json:"expiresIn"`
+
+	// RefreshExpiresIn refresh token 유효 기간(초)
+	RefreshExpiresIn int `json:"refreshExpiresIn"`
+
+	// RefreshToken 한 번만 사용 가능. /auth/refresh 에서 새 token 으로 교체됨
+	RefreshToken string `json:"refreshToken"`
+
+	// TokenType 항상 Bearer
+	TokenType TokenInfoTokenType `json:"tokenType"`
+}
+
+// TokenInfoTokenType 항상 Bearer
+type TokenInfoTokenType string
+
+// TokenResponse defines model for TokenResponse.
+type TokenResponse struct {
+	// Code HTTP Status 코드
+	Code int        `json:"code"`
+	Data *TokenInfo `json:"data,omitempty"`
+
+	// Message message
+	Message string `json:"message"`
+}
+
+// Violation defines model for Violation.
+type Violation struct {
+	// Field 위치와 필드 (예. body.name, query.pagination)
+	Field string `json:"field"`
+
+	// Message 실패 사유
+	Message string `json:"message"`
+}
+
 // PaginationQueryParam defines model for paginationQueryParam.
 type PaginationQueryParam struct {
+	// Cursor 커서 페이징(page 대신 사용). 첫 페이지는 빈 문자열, 이후는 응답의 nextCursor
+	Cursor *string `json:"cursor,omitempty"`
+
 	// Limit 페이지 당 출력 수
 	Limit *int `json:"limit,omitempty"`
 
@@ -122,6 +545,9 @@ type PaginationQueryParam struct {
 	Page *int `json:"page,omitempty"`
 }
 
+// SessionIdPathParam defines model for sessionIdPathParam.
+type SessionIdPathParam = string
+
 // SortingQueryParam defines model for sortingQueryParam.
 type SortingQueryParam struct {
 	// Dirs string[]
@@ -131,6 +557,12 @@ type SortingQueryParam struct {
 	Keys *[]string `json:"keys,omitempty"`
 }
 
+// TopicQueryParam defines model for topicQueryParam.
+type TopicQueryParam = []string
+
+// UuidPathParam defines model for uuidPathParam.
+type UuidPathParam = openapi_types.UUID
+
 // ListAppusersParams defines parameters for ListAppusers.
 type ListAppusersParams struct {
 	// Uuid 사용자 uuid
@@ -152,8 +584,44 @@ type ListAppusersParams struct {
 	Pagination *PaginationQueryParam `form:"pagination,omitempty" json:"pagination,omitempty"`
 }
 
+// SseOpenParams defines parameters for SseOpen.
+type SseOpenParams struct {
+	// Topic 구독할 topic (name 또는 name:key, 예 user:{uuid}, appuser.updated). 여러 개면 반복
+	Topic TopicQueryParam `form:"topic" json:"topic"`
+
+	// LastEventId Last-Event-ID 헤더를 보낼 수 없을 때 (새 ticket 으로 EventSource 를 다시 만들 때). 헤더가 우선
+	LastEventId *string `form:"lastEventId,omitempty" json:"lastEventId,omitempty"`
+}
+
+// WsOpenParams defines parameters for WsOpen.
+type WsOpenParams struct {
+	// Topic 구독할 topic (name 또는 name:key, 예 user:{uuid}, appuser.updated). 여러 개면 반복
+	Topic TopicQueryParam `form:"topic" json:"topic"`
+}
+
+// CreateApiKeyJSONRequestBody defines body for CreateApiKey for application/json ContentType.
+type CreateApiKeyJSONRequestBody = CreateApiKeyRequest
+
+// RevokeTokenJSONRequestBody defines body for RevokeToken for application/json ContentType.
+type RevokeTokenJSONRequestBody = RevokeTokenRequest
+
 // CreateAppuserJSONRequestBody defines body for CreateAppuser for application/json ContentType.
 type CreateAppuserJSONRequestBody = CreateAppuserRequest
 
 // UpdateAppuserJSONRequestBody defines body for UpdateAppuser for application/json ContentType.
 type UpdateAppuserJSONRequestBody = Appuser
+
+// PatchAppuserJSONRequestBody defines body for PatchAppuser for application/json ContentType.
+type PatchAppuserJSONRequestBody = PatchAppuserRequest
+
+// LoginJSONRequestBody defines body for Login for application/json ContentType.
+type LoginJSONRequestBody = LoginRequest
+
+// LogoutJSONRequestBody defines body for Logout for application/json ContentType.
+type LogoutJSONRequestBody = LogoutRequest
+
+// RefreshTokenJSONRequestBody defines body for RefreshToken for application/json ContentType.
+type RefreshTokenJSONRequestBody = RefreshRequest
+
+// PublishEventJSONRequestBody defines body for PublishEvent for application/json ContentType.
+type PublishEventJSONRequestBody = PublishEventRequest
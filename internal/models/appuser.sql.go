@@ -8,13 +8,76 @@ package models
 import (
 	"context"
 
+	"github.com/lib/pq"
 	null "gopkg.in/guregu/null.v4"
 )
 
+const anonymizeWithdrawnAppusers = `-- name: AnonymizeWithdrawnAppusers :many
+UPDATE appuser
+SET name          = '',
+    birthday      = DATE '1900-01-01',
+    anonymized_at = now()
+WHERE withdraw = true
+  AND anonymized_at IS NULL
+  AND withdrawn_at < $1::timestamptz
+RETURNING uuid
+`
+
+func (q *Queries) AnonymizeWithdrawnAppusers(ctx context.Context, withdrawnBefore null.Time) ([]null.String, error) {
+	rows, err := q.db.QueryContext(ctx, anonymizeWithdrawnAppusers, withdrawnBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []null.String{}
+	for rows.Next() {
+		var uuid null.String
+		if err := rows.Scan(&uuid); err != nil {
+			return nil, err
+		}
+		items = append(items, uuid)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countAppusers = `-- name: CountAppusers :one
+SELECT count(*)
+FROM appuser
+WHERE ($1::varchar IS NULL OR uuid = CAST($1 AS UUID))
+  AND ($2::varchar IS NULL OR name = $2)
+  AND ($3::enum_gender IS NULL OR gender = $3)
+  AND ($4::boolean IS NULL OR withdraw = $4)
+`
+
+type CountAppusersParams struct {
+	UUID     null.String `db:"uuid"`
+	Name     null.String `db:"name"`
+	Gender   null.String `db:"gender"`
+	Withdraw null.Bool   `db:"withdraw"`
+}
+
+func (q *Queries) CountAppusers(ctx context.Context, arg CountAppusersParams) (null.Int, error) {
+	row := q.db.QueryRowContext(ctx, countAppusers,
+		arg.UUID,
+		arg.Name,
+		arg.Gender,
+		arg.Withdraw,
+	)
+	var count null.Int
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAppuser = `-- name: CreateAppuser :one
 INSERT INTO appuser (name, birthday, gender, withdraw)
 VALUES ($1, $2, $3, $4)
-RETURNING id, uuid, created_at, modified_at, name, birthday, gender, withdraw
+RETURNING id, uuid, created_at, modified_at, name, birthday, gender, withdraw, withdrawn_at, anonymized_at, role, version
 `
 
 type CreateAppuserParams struct {
@@ -41,12 +104,30 @@ func (q *Queries) CreateAppuser(ctx context.Context, arg CreateAppuserParams) (A
 		&i.Birthday,
 		&i.Gender,
 		&i.Withdraw,
+		&i.WithdrawnAt,
+		&i.AnonymizedAt,
+		&i.Role,
+		&i.Version,
 	)
 	return i, err
 }
 
+const deleteAppuser = `-- name: DeleteAppuser :execrows
+DELETE
+FROM appuser
+WHERE uuid = $1
+`
+
+func (q *Queries) DeleteAppuser(ctx context.Context, uuid null.String) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAppuser, uuid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getAllAppusers = `-- name: GetAllAppusers :many
-SELECT id, uuid, created_at, modified_at, name, birthday, gender, withdraw
+SELECT id, uuid, created_at, modified_at, name, birthday, gender, withdraw, withdrawn_at, anonymized_at, role, version
 FROM appuser
 `
 
@@ -68,6 +149,11 @@ func (q *Queries) GetAllAppusers(ctx context.Context) ([]AppuserBlock, error) {
 			&i.Birthday,
 			&i.Gender,
 			&i.Withdraw,
+			&i.WithdrawnAt,
+			&i.AnonymizedAt,
+			&i.Role,
+			&i.Version,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -82,8 +168,60 @@ func (q *Queries) GetAllAppusers(ctx context.Context) ([]AppuserBlock, error) {
 	return items, nil
 }
 
+const getAppuser = `-- name: GetAppuser :one
+SELECT id, uuid, created_at, modified_at, name, birthday, gender, withdraw, withdrawn_at, anonymized_at, role, version
+FROM appuser
+WHERE uuid = $1
+`
+
+func (q *Queries) GetAppuser(ctx context.Context, uuid null.String) (AppuserBlock, error) {
+	row := q.db.QueryRowContext(ctx, getAppuser, uuid)
+	var i AppuserBlock
+	err := row.Scan(
+		&i.ID,
+		&i.UUID,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.Name,
+		&i.Birthday,
+		&i.Gender,
+		&i.Withdraw,
+		&i.WithdrawnAt,
+		&i.AnonymizedAt,
+		&i.Role,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getAppuserByID = `-- name: GetAppuserByID :one
+SELECT id, uuid, created_at, modified_at, name, birthday, gender, withdraw, withdrawn_at, anonymized_at, role, version
+FROM appuser
+WHERE id = $1
+`
+
+func (q *Queries) GetAppuserByID(ctx context.Context, id null.Int) (AppuserBlock, error) {
+	row := q.db.QueryRowContext(ctx, getAppuserByID, id)
+	var i AppuserBlock
+	err := row.Scan(
+		&i.ID,
+		&i.UUID,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.Name,
+		&i.Birthday,
+		&i.Gender,
+		&i.Withdraw,
+		&i.WithdrawnAt,
+		&i.AnonymizedAt,
+		&i.Role,
+		&i.Version,
+	)
+	return i, err
+}
+
 const getAppusersByName = `-- name: GetAppusersByName :one
-SELECT id, uuid, created_at, modified_at, name, birthday, gender, withdraw
+SELECT id, uuid, created_at, modified_at, name, birthday, gender, withdraw, withdrawn_at, anonymized_at, role, version
 FROM appuser
 WHERE name = $1
 `
@@ -100,26 +238,96 @@ func (q *Queries) GetAppusersByName(ctx context.Context, name null.String) (Appu
 		&i.Birthday,
 		&i.Gender,
 		&i.Withdraw,
+		&i.WithdrawnAt,
+		&i.AnonymizedAt,
+		&i.Role,
+		&i.Version,
+	)
+	return i, err
+}
+
+const patchAppuser = `-- name: PatchAppuser :one
+UPDATE appuser
+SET name         = COALESCE($1::varchar, name),
+    birthday     = COALESCE($2::date, birthday),
+    gender       = COALESCE($3::enum_gender, gender),
+    withdraw     = COALESCE($4::boolean, withdraw),
+    withdrawn_at = CASE WHEN COALESCE($4::boolean, withdraw) THEN COALESCE(withdrawn_at, now()) END,
+    role         = COALESCE($5::varchar[], role)
+WHERE appuser.uuid = $6
+  AND ($7::bigint IS NULL OR version = $7)
+RETURNING id, uuid, created_at, modified_at, name, birthday, gender, withdraw, withdrawn_at, anonymized_at, role, version
+`
+
+type PatchAppuserParams struct {
+	Name            null.String `db:"name"`
+	Birthday        null.Time   `db:"birthday"`
+	Gender          null.String `db:"gender"`
+	Withdraw        null.Bool   `db:"withdraw"`
+	Role            []string    `db:"role"`
+	UUID            null.String `db:"uuid"`
+	ExpectedVersion null.Int    `db:"expected_version"`
+}
+
+func (q *Queries) PatchAppuser(ctx context.Context, arg PatchAppuserParams) (AppuserBlock, error) {
+	row := q.db.QueryRowContext(ctx, patchAppuser,
+		arg.Name,
+		arg.Birthday,
+		arg.Gender,
+		arg.Withdraw,
+		pq.Array(arg.Role),
+		arg.UUID,
+		arg.ExpectedVersion,
+	)
+	var i AppuserBlock
+	err := row.Scan(
+		&i.ID,
+		&i.UUID,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.Name,
+		&i.Birthday,
+		&i.Gender,
+		&i.Withdraw,
+		&i.WithdrawnAt,
+		&i.AnonymizedAt,
+		&i.Role,
+		&i.Version,
 	)
 	return i, err
 }
 
 const searchAppusers = `-- name: SearchAppusers :many
-SELECT id, uuid, created_at, modified_at, name, birthday, gender, withdraw
+SELECT id, uuid, created_at, modified_at, name, birthday, gender, withdraw, withdrawn_at, anonymized_at, role, version
 FROM appuser
 WHERE ($1::varchar IS NULL OR uuid = CAST($1 AS UUID))
   AND ($2::varchar IS NULL OR name = $2)
   AND ($3::enum_gender IS NULL OR gender = $3)
   AND ($4::boolean IS NULL OR withdraw = $4)
-          ? 1 = $5::text
+  AND ($5::bigint IS NULL
+    OR ($6::text = 'id' AND $7::text = 'ASC' AND id > $5)
+    OR ($6::text = 'id' AND $7::text = 'DESC' AND id < $5)
+    OR ($6::text = 'name' AND $7::text = 'ASC' AND (name, id) > ($8::varchar, $5))
+    OR ($6::text = 'name' AND $7::text = 'DESC' AND (name, id) < ($8::varchar, $5))
+    OR ($6::text = 'birthday' AND $7::text = 'ASC' AND (birthday, id) > ($9::date, $5))
+    OR ($6::text = 'birthday' AND $7::text = 'DESC' AND (birthday, id) < ($9::date, $5))
+    OR ($6::text = 'created_at' AND $7::text = 'ASC' AND (created_at, id) > ($10::timestamptz, $5))
+    OR ($6::text = 'created_at' AND $7::text = 'DESC' AND (created_at, id) < ($10::timestamptz, $5)))
+          ? 1 = $11::text
 `
 
 type SearchAppusersParams struct {
-	UUID     null.String `db:"uuid"`
-	Name     null.String `db:"name"`
-	Gender   null.String `db:"gender"`
-	Withdraw null.Bool   `db:"withdraw"`
-	Options  null.String `db:"options"`
+	UUID            null.String `db:"uuid"`
+	Name            null.String `db:"name"`
+	Gender          null.String `db:"gender"`
+	Withdraw        null.Bool   `db:"withdraw"`
+	CursorID        null.Int    `db:"cursor_id"`
+	CursorKey       null.String `db:"cursor_key"`
+	CursorDir       null.String `db:"cursor_dir"`
+	CursorName      null.String `db:"cursor_name"`
+	CursorBirthday  null.Time   `db:"cursor_birthday"`
+	CursorCreatedAt null.Time   `db:"cursor_created_at"`
+	Options         null.String `db:"options"`
 }
 
 func (q *Queries) SearchAppusers(ctx context.Context, arg SearchAppusersParams) ([]AppuserBlock, error) {
@@ -128,6 +336,12 @@ func (q *Queries) SearchAppusers(ctx context.Context, arg SearchAppusersParams)
 		arg.Name,
 		arg.Gender,
 		arg.Withdraw,
+		arg.CursorID,
+		arg.CursorKey,
+		arg.CursorDir,
+		arg.CursorName,
+		arg.CursorBirthday,
+		arg.CursorCreatedAt,
 		arg.Options,
 	)
 	if err != nil {
@@ -146,6 +360,11 @@ func (q *Queries) SearchAppusers(ctx context.Context, arg SearchAppusersParams)
 			&i.Birthday,
 			&i.Gender,
 			&i.Withdraw,
+			&i.WithdrawnAt,
+			&i.AnonymizedAt,
+			&i.Role,
+			&i.Version,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -162,30 +381,62 @@ func (q *Queries) SearchAppusers(ctx context.Context, arg SearchAppusersParams)
 
 const updateAppuser = `-- name: UpdateAppuser :one
 UPDATE appuser
-SET name     = $2,
-    birthday = $3,
-    gender   = $4,
-    withdraw = $5
-WHERE appuser.uuid = $1
-RETURNING id, uuid, created_at, modified_at, name, birthday, gender, withdraw
+SET name         = $1,
+    birthday     = $2,
+    gender       = $3,
+    withdraw     = $4,
+    withdrawn_at = CASE WHEN $4 THEN COALESCE(withdrawn_at, now()) END
+WHERE appuser.uuid = $5
+  AND ($6::bigint IS NULL OR version = $6)
+RETURNING id, uuid, created_at, modified_at, name, birthday, gender, withdraw, withdrawn_at, anonymized_at, role, version
 `
 
 type UpdateAppuserParams struct {
-	UUID     null.String `db:"uuid"`
-	Name     null.String `db:"name"`
-	Birthday null.Time   `db:"birthday"`
-	Gender   null.String `db:"gender"`
-	Withdraw null.Bool   `db:"withdraw"`
+	Name            null.String `db:"name"`
+	Birthday        null.Time   `db:"birthday"`
+	Gender          null.String `db:"gender"`
+	Withdraw        null.Bool   `db:"withdraw"`
+	UUID            null.String `db:"uuid"`
+	ExpectedVersion null.Int    `db:"expected_version"`
 }
 
 func (q *Queries) UpdateAppuser(ctx context.Context, arg UpdateAppuserParams) (AppuserBlock, error) {
 	row := q.db.QueryRowContext(ctx, updateAppuser,
-		arg.UUID,
 		arg.Name,
 		arg.Birthday,
 		arg.Gender,
 		arg.Withdraw,
+		arg.UUID,
+		arg.ExpectedVersion,
+	)
+	var i AppuserBlock
+	err := row.Scan(
+		&i.ID,
+		&i.UUID,
+		&i.CreatedAt,
+		&i.ModifiedAt,
+		&i.Name,
+		&i.Birthday,
+		&i.Gender,
+		&i.Withdraw,
+		&i.WithdrawnAt,
+		&i.AnonymizedAt,
+		&i.Role,
+		&i.Version,
 	)
+	return i, err
+}
+
+const withdrawAppuser = `-- name: WithdrawAppuser :one
+UPDATE appuser
+SET withdraw     = true,
+    withdrawn_at = COALESCE(withdrawn_at, now())
+WHERE appuser.uuid = $1
+RETURNING id, uuid, created_at, modified_at, name, birthday, gender, withdraw, withdrawn_at, anonymized_at, role, version
+`
+
+func (q *Queries) WithdrawAppuser(ctx context.Context, uuid null.String) (AppuserBlock, error) {
+	row := q.db.QueryRowContext(ctx, withdrawAppuser, uuid)
 	var i AppuserBlock
 	err := row.Scan(
 		&i.ID,
@@ -196,6 +447,10 @@ func (q *Queries) UpdateAppuser(ctx context.Context, arg UpdateAppuserParams) (A
 		&i.Birthday,
 		&i.Gender,
 		&i.Withdraw,
+		&i.WithdrawnAt,
+		&i.AnonymizedAt,
+		&i.Role,
+		&i.Version,
 	)
 	return i, err
 }
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"context"
+	"sync"
+
+	"fiber-boilerplate/internal/pkg/database"
+)
+
+// AppuserChangedFunc : appuser 가 수정/삭제된 뒤 호출. 트랜잭션 안이면 commit 후에만 호출
+type AppuserChangedFunc func(ctx context.Context, uuid string)
+
+var (
+	appuserChangedHooks []AppuserChangedFunc
+	hooksMu             sync.RWMutex
+)
+
+// OnAppuserChanged : 세션 캐시 무효화 등. Setup 단계에서 등록
+func OnAppuserChanged(fn AppuserChangedFunc) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	appuserChangedHooks = append(appuserChangedHooks, fn)
+}
+
+// appuserChanged : tx 가 트랜잭션이면 commit 후로 미룸 (rollback 되면 호출하지 않음)
+func appuserChanged(tx *Queries, qctx context.Context, uuid string) {
+	if uuid == "" {
+		return
+	}
+	if qctx == nil {
+		qctx = context.Background()
+	}
+	// 요청이 끝난 뒤 commit 될 수도 있으므로 취소는 따르지 않음
+	qctx = context.WithoutCancel(qctx)
+
+	notify := func() {
+		hooksMu.RLock()
+		hooks := appuserChangedHooks
+		hooksMu.RUnlock()
+		for _, fn := range hooks {
+			fn(qctx, uuid)
+		}
+	}
+
+	if tx != nil {
+		if sqltx, ok := tx.db.(*database.SQLTX); ok {
+			sqltx.AfterCommit(notify)
+			return
+		}
+	}
+	notify()
+}
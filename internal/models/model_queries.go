@@ -3,6 +3,7 @@ package models
 import (
 	"context"
 	"errors"
+	"time"
 
 	"gopkg.in/guregu/null.v4"
 )
@@ -15,17 +16,59 @@ var Appuser AppuserQuery = new(AppuserBlock)
 
 var ArrayTest ArrayTestQuery = new(ArrayTestBlock)
 
+var AppuserCredential AppuserCredentialQuery = new(AppuserCredentialBlock)
+
+var RefreshToken RefreshTokenQuery = new(RefreshTokenBlock)
+
+var ApiKey ApiKeyQuery = new(ApiKeyBlock)
+
+// SearchAppusersSortColumns : SearchAppusers 에서 정렬 가능한 컬럼
+var SearchAppusersSortColumns = SortColumns{
+	"name":       "name",
+	"birthday":   "birthday",
+	"created_at": "created_at",
+}
+
 type AppuserQuery interface {
 	GetAppusersByName(qctx context.Context, exid string) (AppuserBlock, error)
-	SearchAppusers(qctx context.Context, param SearchAppusersParams) ([]AppuserBlock, error)
+	GetAppuser(qctx context.Context, uuid string) (AppuserBlock, error)
+	GetAppuserByID(tx *Queries, qctx context.Context, id int64) (AppuserBlock, error)
+	SearchAppusers(tx *Queries, qctx context.Context, param SearchAppusersParams) ([]AppuserBlock, error)
+	CountAppusers(tx *Queries, qctx context.Context, param CountAppusersParams) (int, error)
 	CreateAppuser(tx *Queries, qctx context.Context, param CreateAppuserParams) (AppuserBlock, error)
 	UpdateAppuser(tx *Queries, qctx context.Context, param UpdateAppuserParams) (AppuserBlock, error)
+	PatchAppuser(tx *Queries, qctx context.Context, param PatchAppuserParams) (AppuserBlock, error)
+	DeleteAppuser(tx *Queries, qctx context.Context, uuid string) (int64, error)
+	WithdrawAppuser(tx *Queries, qctx context.Context, uuid string) (AppuserBlock, error)
+	AnonymizeWithdrawnAppusers(qctx context.Context, withdrawnBefore time.Time) ([]string, error)
 }
 
 type ArrayTestQuery interface {
 	GetAllColumns(qctx context.Context) ([]ArrayTestBlock, error)
 }
 
+type AppuserCredentialQuery interface {
+	GetAppuserCredential(qctx context.Context, login string) (AppuserCredentialBlock, error)
+	CreateAppuserCredential(tx *Queries, qctx context.Context, param CreateAppuserCredentialParams) (AppuserCredentialBlock, error)
+}
+
+type RefreshTokenQuery interface {
+	CreateRefreshToken(tx *Queries, qctx context.Context, param CreateRefreshTokenParams) (RefreshTokenBlock, error)
+	GetRefreshTokenForUpdate(tx *Queries, qctx context.Context, tokenHash string) (RefreshTokenBlock, error)
+	UseRefreshToken(tx *Queries, qctx context.Context, id int64) (int64, error)
+	RevokeRefreshTokenFamily(tx *Queries, qctx context.Context, family string) (int64, error)
+	RevokeAppuserRefreshTokens(tx *Queries, qctx context.Context, uuid string) (int64, error)
+	DeleteExpiredRefreshTokens(qctx context.Context, expiredBefore time.Time) (int64, error)
+}
+
+type ApiKeyQuery interface {
+	CreateApiKey(tx *Queries, qctx context.Context, param CreateApiKeyParams) (ApiKeyBlock, error)
+	GetApiKeyByHash(qctx context.Context, keyHash string) (ApiKeyBlock, error)
+	ListApiKeys(qctx context.Context) ([]ApiKeyBlock, error)
+	RevokeApiKey(tx *Queries, qctx context.Context, uuid string) (ApiKeyBlock, error)
+	TouchApiKey(qctx context.Context, id int64) (int64, error)
+}
+
 // AppuserBlock :
 func (m *AppuserBlock) GetAppusersByName(qctx context.Context, exid string) (AppuserBlock, error) {
 	if qctx == nil {
@@ -34,11 +77,56 @@ func (m *AppuserBlock) GetAppusersByName(qctx context.Context, exid string) (App
 	return query().GetAppusersByName(qctx, null.StringFrom(exid))
 }
 
-func (m *AppuserBlock) SearchAppusers(qctx context.Context, param SearchAppusersParams) ([]AppuserBlock, error) {
+func (m *AppuserBlock) GetAppuser(qctx context.Context, uuid string) (AppuserBlock, error) {
 	if qctx == nil {
 		qctx = context.Background()
 	}
-	return query().SearchAppusers(qctx, param)
+	return query().GetAppuser(qctx, null.StringFrom(uuid))
+}
+
+func (m *AppuserBlock) GetAppuserByID(tx *Queries, qctx context.Context, id int64) (AppuserBlock, error) {
+	if tx == nil {
+		if qctx == nil {
+			qctx = context.Background()
+		}
+		return query().GetAppuserByID(qctx, null.IntFrom(id))
+	} else {
+		if qctx == nil {
+			return AppuserBlock{}, errors.New("qctx is nil")
+		}
+		return tx.GetAppuserByID(qctx, null.IntFrom(id))
+	}
+}
+
+func (m *AppuserBlock) SearchAppusers(tx *Queries, qctx context.Context, param SearchAppusersParams) ([]AppuserBlock, error) {
+	if tx == nil {
+		if qctx == nil {
+			qctx = context.Background()
+		}
+		return query().SearchAppusers(qctx, param)
+	} else {
+		if qctx == nil {
+			return nil, errors.New("qctx is nil")
+		}
+		return tx.SearchAppusers(qctx, param)
+	}
+}
+
+func (m *AppuserBlock) CountAppusers(tx *Queries, qctx context.Context, param CountAppusersParams) (int, error) {
+	var count null.Int
+	var err error
+	if tx == nil {
+		if qctx == nil {
+			qctx = context.Background()
+		}
+		count, err = query().CountAppusers(qctx, param)
+	} else {
+		if qctx == nil {
+			return 0, errors.New("qctx is nil")
+		}
+		count, err = tx.CountAppusers(qctx, param)
+	}
+	return int(count.Int64), err
 }
 
 func (m *AppuserBlock) CreateAppuser(tx *Queries, qctx context.Context, param CreateAppuserParams) (AppuserBlock, error) {
@@ -55,7 +143,13 @@ func (m *AppuserBlock) CreateAppuser(tx *Queries, qctx context.Context, param Cr
 	}
 }
 
-func (m *AppuserBlock) UpdateAppuser(tx *Queries, qctx context.Context, param UpdateAppuserParams) (AppuserBlock, error) {
+func (m *AppuserBlock) UpdateAppuser(tx *Queries, qctx context.Context, param UpdateAppuserParams) (entity AppuserBlock, err error) {
+	defer func() {
+		if err == nil {
+			appuserChanged(tx, qctx, entity.UUID.String)
+		}
+	}()
+
 	if tx == nil {
 		if qctx == nil {
 			qctx = context.Background()
@@ -69,6 +163,84 @@ func (m *AppuserBlock) UpdateAppuser(tx *Queries, qctx context.Context, param Up
 	}
 }
 
+func (m *AppuserBlock) PatchAppuser(tx *Queries, qctx context.Context, param PatchAppuserParams) (entity AppuserBlock, err error) {
+	defer func() {
+		if err == nil {
+			appuserChanged(tx, qctx, entity.UUID.String)
+		}
+	}()
+
+	if tx == nil {
+		if qctx == nil {
+			qctx = context.Background()
+		}
+		return query().PatchAppuser(qctx, param)
+	} else {
+		if qctx == nil {
+			return AppuserBlock{}, errors.New("qctx is nil")
+		}
+		return tx.PatchAppuser(qctx, param)
+	}
+}
+
+func (m *AppuserBlock) DeleteAppuser(tx *Queries, qctx context.Context, uuid string) (affected int64, err error) {
+	defer func() {
+		if err == nil && affected > 0 {
+			appuserChanged(tx, qctx, uuid)
+		}
+	}()
+
+	if tx == nil {
+		if qctx == nil {
+			qctx = context.Background()
+		}
+		return query().DeleteAppuser(qctx, null.StringFrom(uuid))
+	} else {
+		if qctx == nil {
+			return 0, errors.New("qctx is nil")
+		}
+		return tx.DeleteAppuser(qctx, null.StringFrom(uuid))
+	}
+}
+
+func (m *AppuserBlock) WithdrawAppuser(tx *Queries, qctx context.Context, uuid string) (entity AppuserBlock, err error) {
+	defer func() {
+		if err == nil {
+			appuserChanged(tx, qctx, entity.UUID.String)
+		}
+	}()
+
+	if tx == nil {
+		if qctx == nil {
+			qctx = context.Background()
+		}
+		return query().WithdrawAppuser(qctx, null.StringFrom(uuid))
+	} else {
+		if qctx == nil {
+			return AppuserBlock{}, errors.New("qctx is nil")
+		}
+		return tx.WithdrawAppuser(qctx, null.StringFrom(uuid))
+	}
+}
+
+// AnonymizeWithdrawnAppusers : 익명화한 사용자의 uuid
+func (m *AppuserBlock) AnonymizeWithdrawnAppusers(qctx context.Context, withdrawnBefore time.Time) ([]string, error) {
+	if qctx == nil {
+		qctx = context.Background()
+	}
+	rows, err := query().AnonymizeWithdrawnAppusers(qctx, null.TimeFrom(withdrawnBefore))
+	if err != nil {
+		return nil, err
+	}
+
+	uuids := make([]string, 0, len(rows))
+	for _, uuid := range rows {
+		appuserChanged(nil, qctx, uuid.String)
+		uuids = append(uuids, uuid.String)
+	}
+	return uuids, nil
+}
+
 // ArrayTestBlock :
 func (m *ArrayTestBlock) GetAllColumns(qctx context.Context) ([]ArrayTestBlock, error) {
 	if qctx == nil {
@@ -76,3 +248,148 @@ func (m *ArrayTestBlock) GetAllColumns(qctx context.Context) ([]ArrayTestBlock,
 	}
 	return query().GetAllColumns(qctx)
 }
+
+// AppuserCredentialBlock :
+func (m *AppuserCredentialBlock) GetAppuserCredential(qctx context.Context, login string) (AppuserCredentialBlock, error) {
+	if qctx == nil {
+		qctx = context.Background()
+	}
+	return query().GetAppuserCredential(qctx, null.StringFrom(login))
+}
+
+func (m *AppuserCredentialBlock) CreateAppuserCredential(tx *Queries, qctx context.Context, param CreateAppuserCredentialParams) (AppuserCredentialBlock, error) {
+	if tx == nil {
+		if qctx == nil {
+			qctx = context.Background()
+		}
+		return query().CreateAppuserCredential(qctx, param)
+	} else {
+		if qctx == nil {
+			return AppuserCredentialBlock{}, errors.New("qctx is nil")
+		}
+		return tx.CreateAppuserCredential(qctx, param)
+	}
+}
+
+// RefreshTokenBlock :
+func (m *RefreshTokenBlock) CreateRefreshToken(tx *Queries, qctx context.Context, param CreateRefreshTokenParams) (RefreshTokenBlock, error) {
+	if tx == nil {
+		if qctx == nil {
+			qctx = context.Background()
+		}
+		return query().CreateRefreshToken(qctx, param)
+	} else {
+		if qctx == nil {
+			return RefreshTokenBlock{}, errors.New("qctx is nil")
+		}
+		return tx.CreateRefreshToken(qctx, param)
+	}
+}
+
+// GetRefreshTokenForUpdate : row lock 이 의미 있도록 transaction 안에서만 사용
+func (m *RefreshTokenBlock) GetRefreshTokenForUpdate(tx *Queries, qctx context.Context, tokenHash string) (RefreshTokenBlock, error) {
+	if tx == nil || qctx == nil {
+		return RefreshTokenBlock{}, errors.New("tx or qctx is nil")
+	}
+	return tx.GetRefreshTokenForUpdate(qctx, null.StringFrom(tokenHash))
+}
+
+func (m *RefreshTokenBlock) UseRefreshToken(tx *Queries, qctx context.Context, id int64) (int64, error) {
+	if tx == nil {
+		if qctx == nil {
+			qctx = context.Background()
+		}
+		return query().UseRefreshToken(qctx, null.IntFrom(id))
+	} else {
+		if qctx == nil {
+			return 0, errors.New("qctx is nil")
+		}
+		return tx.UseRefreshToken(qctx, null.IntFrom(id))
+	}
+}
+
+func (m *RefreshTokenBlock) RevokeRefreshTokenFamily(tx *Queries, qctx context.Context, family string) (int64, error) {
+	if tx == nil {
+		if qctx == nil {
+			qctx = context.Background()
+		}
+		return query().RevokeRefreshTokenFamily(qctx, null.StringFrom(family))
+	} else {
+		if qctx == nil {
+			return 0, errors.New("qctx is nil")
+		}
+		return tx.RevokeRefreshTokenFamily(qctx, null.StringFrom(family))
+	}
+}
+
+func (m *RefreshTokenBlock) RevokeAppuserRefreshTokens(tx *Queries, qctx context.Context, uuid string) (int64, error) {
+	if tx == nil {
+		if qctx == nil {
+			qctx = context.Background()
+		}
+		return query().RevokeAppuserRefreshTokens(qctx, null.StringFrom(uuid))
+	} else {
+		if qctx == nil {
+			return 0, errors.New("qctx is nil")
+		}
+		return tx.RevokeAppuserRefreshTokens(qctx, null.StringFrom(uuid))
+	}
+}
+
+func (m *RefreshTokenBlock) DeleteExpiredRefreshTokens(qctx context.Context, expiredBefore time.Time) (int64, error) {
+	if qctx == nil {
+		qctx = context.Background()
+	}
+	return query().DeleteExpiredRefreshTokens(qctx, null.TimeFrom(expiredBefore))
+}
+
+// ApiKeyBlock :
+func (m *ApiKeyBlock) CreateApiKey(tx *Queries, qctx context.Context, param CreateApiKeyParams) (ApiKeyBlock, error) {
+	if tx == nil {
+		if qctx == nil {
+			qctx = context.Background()
+		}
+		return query().CreateApiKey(qctx, param)
+	} else {
+		if qctx == nil {
+			return ApiKeyBlock{}, errors.New("qctx is nil")
+		}
+		return tx.CreateApiKey(qctx, param)
+	}
+}
+
+func (m *ApiKeyBlock) GetApiKeyByHash(qctx context.Context, keyHash string) (ApiKeyBlock, error) {
+	if qctx == nil {
+		qctx = context.Background()
+	}
+	return query().GetApiKeyByHash(qctx, null.StringFrom(keyHash))
+}
+
+func (m *ApiKeyBlock) ListApiKeys(qctx context.Context) ([]ApiKeyBlock, error) {
+	if qctx == nil {
+		qctx = context.Background()
+	}
+	return query().ListApiKeys(qctx)
+}
+
+func (m *ApiKeyBlock) RevokeApiKey(tx *Queries, qctx context.Context, uuid string) (ApiKeyBlock, error) {
+	if tx == nil {
+		if qctx == nil {
+			qctx = context.Background()
+		}
+		return query().RevokeApiKey(qctx, null.StringFrom(uuid))
+	} else {
+		if qctx == nil {
+			return ApiKeyBlock{}, errors.New("qctx is nil")
+		}
+		return tx.RevokeApiKey(qctx, null.StringFrom(uuid))
+	}
+}
+
+// TouchApiKey : last_used_at 은 1분에 한 번만 갱신 (요청마다 쓰지 않도록)
+func (m *ApiKeyBlock) TouchApiKey(qctx context.Context, id int64) (int64, error) {
+	if qctx == nil {
+		qctx = context.Background()
+	}
+	return query().TouchApiKey(qctx, null.IntFrom(id))
+}
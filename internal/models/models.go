@@ -8,6 +8,7 @@ import (
 	"database/sql/driver"
 	"fmt"
 
+	"github.com/lib/pq"
 	null "gopkg.in/guregu/null.v4"
 )
 
@@ -53,15 +54,41 @@ func (ns NullEnumGender) Value() (driver.Value, error) {
 	return string(ns.EnumGender), nil
 }
 
+type ApiKeyBlock struct {
+	ID         null.Int       `db:"id"`
+	UUID       null.String    `db:"uuid"`
+	CreatedAt  null.Time      `db:"created_at"`
+	Name       null.String    `db:"name"`
+	Owner      null.String    `db:"owner"`
+	Prefix     null.String    `db:"prefix"`
+	KeyHash    null.String    `db:"key_hash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	ExpiresAt  null.Time      `db:"expires_at"`
+	LastUsedAt null.Time      `db:"last_used_at"`
+	RevokedAt  null.Time      `db:"revoked_at"`
+}
+
 type AppuserBlock struct {
-	ID         null.Int    `db:"id"`
-	UUID       null.String `db:"uuid"`
-	CreatedAt  null.Time   `db:"created_at"`
-	ModifiedAt null.Time   `db:"modified_at"`
-	Name       null.String `db:"name"`
-	Birthday   null.Time   `db:"birthday"`
-	Gender     null.String `db:"gender"`
-	Withdraw   null.Bool   `db:"withdraw"`
+	ID           null.Int       `db:"id"`
+	UUID         null.String    `db:"uuid"`
+	CreatedAt    null.Time      `db:"created_at"`
+	ModifiedAt   null.Time      `db:"modified_at"`
+	Name         null.String    `db:"name"`
+	Birthday     null.Time      `db:"birthday"`
+	Gender       null.String    `db:"gender"`
+	Withdraw     null.Bool      `db:"withdraw"`
+	WithdrawnAt  null.Time      `db:"withdrawn_at"`
+	AnonymizedAt null.Time      `db:"anonymized_at"`
+	Role         pq.StringArray `db:"role"`
+	Version      null.Int       `db:"version"`
+}
+
+type AppuserCredentialBlock struct {
+	AppuserID    null.Int    `db:"appuser_id"`
+	CreatedAt    null.Time   `db:"created_at"`
+	ModifiedAt   null.Time   `db:"modified_at"`
+	Login        null.String `db:"login"`
+	PasswordHash null.String `db:"password_hash"`
 }
 
 type ArrayTestBlock struct {
@@ -75,3 +102,14 @@ type ArrayTestBlock struct {
 	FloatArrayField   []float64     `db:"float_array_field"`
 	BoolArrayField    []null.Bool   `db:"bool_array_field"`
 }
+
+type RefreshTokenBlock struct {
+	ID        null.Int    `db:"id"`
+	AppuserID null.Int    `db:"appuser_id"`
+	CreatedAt null.Time   `db:"created_at"`
+	Family    null.String `db:"family"`
+	TokenHash null.String `db:"token_hash"`
+	ExpiresAt null.Time   `db:"expires_at"`
+	UsedAt    null.Time   `db:"used_at"`
+	RevokedAt null.Time   `db:"revoked_at"`
+}
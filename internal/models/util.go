@@ -1,8 +1,13 @@
 package models
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
 
+	"fiber-boilerplate/internal/pkg/database"
 	"fiber-boilerplate/internal/pkg/util"
 
 	"gopkg.in/guregu/null.v4"
@@ -14,10 +19,81 @@ type ListOptions struct {
 	Pagination PaginationBlock
 }
 
+// SortDir : 정렬 방향
+type SortDir string
+
+// SortDirs
+const (
+	SortAsc  SortDir = "ASC"
+	SortDesc SortDir = "DESC"
+)
+
+// ParseSortDir : ASC / DESC 외의 값은 거부 (대소문자 무시)
+func ParseSortDir(dir string) (SortDir, error) {
+	switch SortDir(strings.ToUpper(strings.TrimSpace(dir))) {
+	case SortAsc:
+		return SortAsc, nil
+	case SortDesc:
+		return SortDesc, nil
+	default:
+		return "", fmt.Errorf("invalid sorting direction: %q (must be ASC or DESC)", dir)
+	}
+}
+
+// SortColumns : 쿼리별 정렬 허용 컬럼 (API sort key -> SQL column identifier)
+type SortColumns map[string]string
+
+// Sorting : whitelist 에 있는 key 만 허용하여 SortingBlock 생성
+func (c SortColumns) Sorting(keys []string, dirs []string) (*SortingBlock, error) {
+	var sorting SortingBlock
+
+	if len(keys) == 0 {
+		if len(dirs) > 0 {
+			return nil, fmt.Errorf("sorting parameter mismatch: keys=%d, dirs=%d", len(keys), len(dirs))
+		}
+		return &sorting, nil
+	}
+	if len(dirs) > 0 && len(keys) != len(dirs) {
+		return nil, fmt.Errorf("sorting parameter mismatch: keys=%d, dirs=%d", len(keys), len(dirs))
+	}
+
+	for i, key := range keys {
+		column, ok := c[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown sorting key: %q", key)
+		}
+
+		dir := SortAsc
+		if len(dirs) > 0 {
+			var err error
+			dir, err = ParseSortDir(dirs[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		sorting.Orders = append(sorting.Orders, OrderBlock{
+			Key:    key,
+			Column: column,
+			Dir:    dir,
+		})
+	}
+	sorting.Provided = true
+
+	return &sorting, nil
+}
+
+// OrderBlock :
+type OrderBlock struct {
+	Key    string
+	Column string
+	Dir    SortDir
+}
+
 // SortingBlock :
 type SortingBlock struct {
 	Provided bool
-	Orders   []string
+	Orders   []OrderBlock
 }
 
 // PaginationBlock :
@@ -25,6 +101,56 @@ type PaginationBlock struct {
 	Provided bool
 	Limit    int
 	Offset   int
+
+	// Keyset : cursor 페이징 여부. Cursor 가 nil 이면 첫 페이지
+	Keyset bool
+	Cursor *CursorBlock
+}
+
+// CursorKeyID : 정렬 key 없이 id 로만 cursor 페이징할 때의 key
+const CursorKeyID = "id"
+
+// CursorBlock : cursor 페이징에서 마지막으로 받은 row 의 위치
+type CursorBlock struct {
+	Key   string  `json:"k"`
+	Dir   SortDir `json:"d"`
+	Value string  `json:"v,omitempty"`
+	ID    int64   `json:"i"`
+}
+
+// Encode : 클라이언트에 전달할 opaque 문자열
+func (c *CursorBlock) Encode() string {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor : 빈 문자열이면 첫 페이지(nil)
+func (c SortColumns) DecodeCursor(encoded string) (*CursorBlock, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var cursor CursorBlock
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	if _, ok := c[cursor.Key]; !ok && cursor.Key != CursorKeyID {
+		return nil, fmt.Errorf("invalid cursor: unknown key %q", cursor.Key)
+	}
+	if cursor.Dir, err = ParseSortDir(string(cursor.Dir)); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	return &cursor, nil
 }
 
 func MakeListOptions(sorting *SortingBlock, pagination *PaginationBlock) (options *ListOptions) {
@@ -40,8 +166,8 @@ func MakeListOptions(sorting *SortingBlock, pagination *PaginationBlock) (option
 	return
 }
 
-// Parameterize :
-func (l *ListOptions) Parameterize() (option null.String) {
+// Parameterize : ? 1 = @options::text 에 넘길 옵션 절. 허용되지 않는 형태이면 database.ErrInvalidOptionClause
+func (l *ListOptions) Parameterize() (option null.String, err error) {
 	orderBy := func() null.String {
 		if l.Sorting.Provided {
 			return l.Sorting.Parameterize()
@@ -59,28 +185,25 @@ func (l *ListOptions) Parameterize() (option null.String) {
 		return
 	}
 
-	option.String = util.String.Join(" ", orderBy.String, pagination.String)
-	option.Valid = true
+	clause := strings.TrimSpace(util.String.Join(" ", orderBy.String, pagination.String))
+	if err = database.ConfirmOptionClause(clause); err != nil {
+		return
+	}
+	option = null.StringFrom(clause)
 
 	return
 }
 
 // Parameterize :
 func (s *SortingBlock) Parameterize() (orderBy null.String) {
-	// Provided : true, Orders : ["osType", "ASC", "version", "DESC"]
-	if s.Provided {
-		for i := range s.Orders {
-			if i == 0 {
-				orderBy.String = util.String.Words("ORDER BY", s.Orders[i])
-				continue
-			}
-			if i%2 == 0 {
-				orderBy.String = util.String.Join(",", orderBy.String, s.Orders[i])
-			} else {
-				orderBy.String += " " + s.Orders[i]
-			}
+	// Provided : true, Orders : [{name ASC}, {birthday DESC}] -> ORDER BY name ASC, birthday DESC
+	// Column 은 SortColumns whitelist 에서 온 값만 사용하므로 사용자 입력이 SQL 에 그대로 들어가지 않는다
+	if s.Provided && len(s.Orders) > 0 {
+		orders := make([]string, 0, len(s.Orders))
+		for _, order := range s.Orders {
+			orders = append(orders, util.String.Words(order.Column, string(order.Dir)))
 		}
-		orderBy.Valid = true
+		orderBy = null.StringFrom(util.String.Words("ORDER BY", util.String.List(orders)))
 	}
 
 	return
@@ -88,7 +211,10 @@ func (s *SortingBlock) Parameterize() (orderBy null.String) {
 
 // Parameterize :
 func (p *PaginationBlock) Parameterize() (pagination null.String) {
-	if p.Provided {
+	if p.Provided && p.Keyset {
+		// 다음 페이지 존재 여부 확인을 위해 한 건 더 조회
+		pagination = null.StringFrom(util.String.Words("LIMIT", strconv.Itoa(p.Limit+1)))
+	} else if p.Provided {
 		pagination = null.StringFrom(util.String.Words("LIMIT", strconv.Itoa(p.Limit), "OFFSET", strconv.Itoa(p.Offset)))
 		pagination.Valid = true
 	}
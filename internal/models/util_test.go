@@ -0,0 +1,141 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"fiber-boilerplate/internal/pkg/database"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestSortColumnsSorting(t *testing.T) {
+	columns := SortColumns{
+		"name":       "name",
+		"created_at": "created_at",
+	}
+
+	tests := []struct {
+		name    string
+		keys    []string
+		dirs    []string
+		want    string
+		wantErr bool
+	}{
+		{"none", nil, nil, "", false},
+		{"default dir", []string{"name"}, nil, "ORDER BY name ASC", false},
+		{"dirs", []string{"name", "created_at"}, []string{"desc", " Asc "}, "ORDER BY name DESC, created_at ASC", false},
+
+		{"unknown key", []string{"password"}, nil, "", true},
+		{"injected key", []string{"name; DROP TABLE appuser"}, nil, "", true},
+		{"quoted key", []string{`"name"`}, nil, "", true},
+		{"case sensitive key", []string{"Name"}, nil, "", true},
+		{"invalid dir", []string{"name"}, []string{"DESCENDING"}, "", true},
+		{"injected dir", []string{"name"}, []string{"ASC, (SELECT 1)"}, "", true},
+		{"empty dir", []string{"name"}, []string{""}, "", true},
+		{"dirs without keys", nil, []string{"ASC"}, "", true},
+		{"mismatch", []string{"name", "created_at"}, []string{"ASC"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorting, err := columns.Sorting(tt.keys, tt.dirs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := sorting.Parameterize().String; got != tt.want {
+				t.Errorf("clause = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	columns := SortColumns{"name": "name"}
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name    string
+		encoded string
+		want    *CursorBlock
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"sort key", (&CursorBlock{Key: "name", Dir: SortAsc, Value: "kim", ID: 7}).Encode(), &CursorBlock{Key: "name", Dir: SortAsc, Value: "kim", ID: 7}, false},
+		{"id key", (&CursorBlock{Key: CursorKeyID, Dir: SortDesc, ID: 42}).Encode(), &CursorBlock{Key: CursorKeyID, Dir: SortDesc, ID: 42}, false},
+		{"lower case dir", encode(`{"k":"name","d":"desc","v":"kim","i":1}`), &CursorBlock{Key: "name", Dir: SortDesc, Value: "kim", ID: 1}, false},
+
+		{"not base64", "!!!", nil, true},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"k":"id","d":"ASC","i":1}`)), nil, true},
+		{"not json", encode("name:kim"), nil, true},
+		{"truncated json", encode(`{"k":"name","d":"ASC"`), nil, true},
+		{"wrong type", encode(`{"k":"name","d":"ASC","i":"1"}`), nil, true},
+		{"unknown key", encode(`{"k":"password","d":"ASC","i":1}`), nil, true},
+		{"injected key", encode(`{"k":"name; DROP TABLE appuser","d":"ASC","i":1}`), nil, true},
+		{"missing key", encode(`{"d":"ASC","i":1}`), nil, true},
+		{"invalid dir", encode(`{"k":"name","d":"DESCENDING","i":1}`), nil, true},
+		{"injected dir", encode(`{"k":"name","d":"ASC, (SELECT 1)","i":1}`), nil, true},
+		{"missing dir", encode(`{"k":"name","i":1}`), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := columns.DecodeCursor(tt.encoded)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want == nil {
+				if cursor != nil {
+					t.Errorf("cursor = %+v, want nil", cursor)
+				}
+				return
+			}
+			if cursor == nil || *cursor != *tt.want {
+				t.Errorf("cursor = %+v, want %+v", cursor, tt.want)
+			}
+		})
+	}
+}
+
+func TestListOptionsParameterize(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ListOptions
+		want    null.String
+		wantErr error
+	}{
+		{"none", ListOptions{}, null.String{}, nil},
+		{"sorting and pagination", ListOptions{
+			Sorting:    SortingBlock{Provided: true, Orders: []OrderBlock{{Column: "name", Dir: SortDesc}}},
+			Pagination: PaginationBlock{Provided: true, Limit: 10, Offset: 20},
+		}, null.StringFrom("ORDER BY name DESC LIMIT 10 OFFSET 20"), nil},
+		{"keyset", ListOptions{
+			Pagination: PaginationBlock{Provided: true, Limit: 10, Keyset: true},
+		}, null.StringFrom("LIMIT 11"), nil},
+
+		{"unlisted column", ListOptions{
+			Sorting: SortingBlock{Provided: true, Orders: []OrderBlock{{Column: "name; DROP TABLE appuser", Dir: SortAsc}}},
+		}, null.String{}, database.ErrInvalidOptionClause},
+		{"negative limit", ListOptions{
+			Pagination: PaginationBlock{Provided: true, Limit: -1},
+		}, null.String{}, database.ErrInvalidOptionClause},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.options.Parameterize()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("option = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
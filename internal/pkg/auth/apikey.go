@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+)
+
+const (
+	// APIKeyPrefix : 로그나 저장소에서 API key 임을 알아볼 수 있도록 붙이는 접두어
+	APIKeyPrefix = "fbk_"
+
+	apiKeyLen = 32
+
+	// apiKeyDisplayLen : 목록에 보여줄 원문 앞부분 길이 (접두어 포함)
+	apiKeyDisplayLen = len(APIKeyPrefix) + 8
+)
+
+// NewAPIKey : 클라이언트에 한 번만 보여줄 원문, 목록에 보여줄 앞부분, DB 에 저장할 hash
+func NewAPIKey() (key string, prefix string, hash string, err error) {
+	raw := make([]byte, apiKeyLen)
+	if _, err = rand.Read(raw); err != nil {
+		return "", "", "", err
+	}
+
+	key = APIKeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	return key, key[:apiKeyDisplayLen], HashAPIKey(key), nil
+}
+
+// HashAPIKey : refresh token 과 같이 salt 없는 sha256 으로 조회
+func HashAPIKey(key string) string {
+	return HashRefreshToken(strings.TrimSpace(key))
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// passwordScheme : 저장 형식 pbkdf2-sha256$<iterations>$<salt>$<hash>
+	passwordScheme = "pbkdf2-sha256"
+	// passwordIterations : OWASP Password Storage Cheat Sheet 권장값
+	passwordIterations = 600000
+	passwordSaltLen    = 16
+	passwordKeyLen     = 32
+)
+
+// ErrInvalidPasswordHash :
+var ErrInvalidPasswordHash = errors.New("auth: invalid password hash")
+
+// dummyHash : 없는 login 에도 같은 시간이 걸리도록 비교할 hash (login 존재 여부 노출 방지)
+var dummyHash = sync.OnceValue(func() string {
+	hash, _ := HashPassword("dummy-password")
+	return hash
+})
+
+// HashPassword :
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordIterations, passwordKeyLen)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		passwordScheme,
+		strconv.Itoa(passwordIterations),
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	}, "$"), nil
+}
+
+// VerifyPassword : encoded 가 비어 있으면 dummyHash 와 비교하고 false
+func VerifyPassword(encoded string, password string) (bool, error) {
+	if encoded == "" {
+		_, _ = verify(dummyHash(), password)
+		return false, nil
+	}
+
+	return verify(encoded, password)
+}
+
+func verify(encoded string, password string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != passwordScheme {
+		return false, ErrInvalidPasswordHash
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false, ErrInvalidPasswordHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false, ErrInvalidPasswordHash
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false, ErrInvalidPasswordHash
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(expected))
+	if err != nil {
+		return false, fmt.Errorf("auth: %w", err)
+	}
+
+	return subtle.ConstantTimeCompare(key, expected) == 1, nil
+}
@@ -0,0 +1,89 @@
+/*
+	토큰 발급
+	access token 은 짧게 유지하는 HS256 JWT, refresh token 은 서버에 hash 로만 저장하는 opaque 문자열
+*/
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const refreshTokenLen = 32
+
+// ErrSigningDisabled : JWT_SECRET 없이 공개키로만 검증하는 경우 (토큰은 외부 IdP 가 발급)
+var ErrSigningDisabled = errors.New("auth: token signing requires JWT_SECRET")
+
+// AccessBlock : access token 발급 설정
+type AccessBlock struct {
+	Secret   string
+	TTL      time.Duration
+	Issuer   string
+	Audience []string
+	Scopes   []string
+}
+
+// SignAccess : uuid, sid, scope, roles 클레임을 담은 access token. jti 는 토큰마다 고유,
+// sid 는 login 한 번에서 이어지는 토큰이 공유 (기기별 세션 ID)
+func (a *AccessBlock) SignAccess(appuserUUID string, sessionID string, roles []string, now time.Time) (string, time.Time, error) {
+	if a.Secret == "" {
+		return "", time.Time{}, ErrSigningDisabled
+	}
+
+	expiresAt := now.Add(a.TTL)
+	// iat 는 같은 초에 사용자 단위 폐기 후 발급된 토큰이 거부되지 않도록 millisecond 까지
+	claims := jwt.MapClaims{
+		"uuid": appuserUUID,
+		"jti":  uuid.NewString(),
+		"iat":  float64(now.UnixMilli()) / 1000,
+		"exp":  expiresAt.Unix(),
+	}
+	if sessionID != "" {
+		claims["sid"] = sessionID
+	}
+	if len(a.Scopes) > 0 {
+		claims["scope"] = strings.Join(a.Scopes, " ")
+	}
+	if len(roles) > 0 {
+		claims["roles"] = roles
+	}
+	if a.Issuer != "" {
+		claims["iss"] = a.Issuer
+	}
+	if len(a.Audience) > 0 {
+		claims["aud"] = a.Audience
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(a.Secret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signed, expiresAt, nil
+}
+
+// NewRefreshToken : 클라이언트에 줄 원문과 DB 에 저장할 hash
+func NewRefreshToken() (token string, hash string, err error) {
+	raw := make([]byte, refreshTokenLen)
+	if _, err = rand.Read(raw); err != nil {
+		return "", "", err
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken : 원문은 충분히 길고 무작위이므로 salt 없는 sha256 으로 조회
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
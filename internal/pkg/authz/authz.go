@@ -0,0 +1,128 @@
+/*
+	scope / role 기반 권한
+	scope 는 RFC 9068 의 scope 클레임(공백 구분 문자열 또는 배열, scp 도 허용),
+	role 은 roles 클레임(배열 또는 공백 구분 문자열)에서 읽는다
+*/
+
+package authz
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const ContextKeyPermission = "fiber-boilerplate/#/permission"
+
+// RoleAdmin : 다른 사용자의 리소스에 접근할 수 있는 role
+const RoleAdmin = "admin"
+
+// PermissionBlock : 요청한 토큰의 scope / role
+type PermissionBlock struct {
+	Scopes []string
+	Roles  []string
+}
+
+// RequirementBlock : operation 하나가 요구하는 권한.
+// Scopes 는 OR 로 묶인 security requirement 별 scope 목록 (각 목록 안은 AND), Roles 는 그 중 하나만 있으면 됨
+type RequirementBlock struct {
+	Scopes [][]string
+	Roles  []string
+}
+
+// FromClaims :
+func FromClaims(claims jwt.MapClaims) *PermissionBlock {
+	p := new(PermissionBlock)
+	if claims == nil {
+		return p
+	}
+
+	p.Scopes = claimValues(claims["scope"])
+	if len(p.Scopes) == 0 {
+		p.Scopes = claimValues(claims["scp"])
+	}
+	p.Roles = claimValues(claims["roles"])
+
+	return p
+}
+
+// FromContext : 인증되지 않은 요청이면 빈 권한
+func FromContext(ctx *fiber.Ctx) *PermissionBlock {
+	if p, ok := ctx.Locals(ContextKeyPermission).(*PermissionBlock); ok {
+		return p
+	}
+	return new(PermissionBlock)
+}
+
+// HasScope :
+func (p *PermissionBlock) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// HasRole :
+func (p *PermissionBlock) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// HasAnyRole :
+func (p *PermissionBlock) HasAnyRole(roles ...string) bool {
+	for _, role := range roles {
+		if p.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// Satisfies : 요구 scope 목록 중 하나를 모두 가지고 있고, 요구 role 중 하나를 가지고 있으면 true.
+// 실패하면 부족한 scope / role 을 함께 반환
+func (p *PermissionBlock) Satisfies(requirement *RequirementBlock) (bool, []string) {
+	if requirement == nil {
+		return true, nil
+	}
+
+	if len(requirement.Scopes) > 0 {
+		var missing []string
+		for i, scopes := range requirement.Scopes {
+			lacking := slices.DeleteFunc(slices.Clone(scopes), p.HasScope)
+			if len(lacking) == 0 {
+				missing = nil
+				break
+			}
+			if i == 0 {
+				missing = lacking
+			}
+		}
+		if missing != nil {
+			return false, missing
+		}
+	}
+
+	if len(requirement.Roles) > 0 && !p.HasAnyRole(requirement.Roles...) {
+		return false, requirement.Roles
+	}
+
+	return true, nil
+}
+
+// claimValues : 공백 구분 문자열 또는 문자열 배열
+func claimValues(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
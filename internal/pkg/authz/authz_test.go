@@ -0,0 +1,77 @@
+package authz
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestFromClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		scopes []string
+		roles  []string
+	}{
+		{"nil", nil, nil, nil},
+		{"scope string", jwt.MapClaims{"scope": "read  write"}, []string{"read", "write"}, nil},
+		{"scope array", jwt.MapClaims{"scope": []interface{}{"read", "", 1, "write"}}, []string{"read", "write"}, nil},
+		{"scp fallback", jwt.MapClaims{"scp": []string{"read"}}, []string{"read"}, nil},
+		{"scope over scp", jwt.MapClaims{"scope": "read", "scp": "write"}, []string{"read"}, nil},
+		{"roles array", jwt.MapClaims{"roles": []interface{}{"admin"}}, nil, []string{"admin"}},
+		{"roles string", jwt.MapClaims{"roles": "admin auditor"}, nil, []string{"admin", "auditor"}},
+		{"unsupported type", jwt.MapClaims{"scope": 1, "roles": map[string]interface{}{"admin": true}}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := FromClaims(tt.claims)
+			if !slices.Equal(p.Scopes, tt.scopes) {
+				t.Errorf("Scopes = %v, want %v", p.Scopes, tt.scopes)
+			}
+			if !slices.Equal(p.Roles, tt.roles) {
+				t.Errorf("Roles = %v, want %v", p.Roles, tt.roles)
+			}
+		})
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		name        string
+		permission  PermissionBlock
+		requirement *RequirementBlock
+		want        bool
+		missing     []string
+	}{
+		{"nil requirement", PermissionBlock{}, nil, true, nil},
+		{"empty requirement", PermissionBlock{}, &RequirementBlock{}, true, nil},
+		{"empty scope list", PermissionBlock{}, &RequirementBlock{Scopes: [][]string{{}}}, true, nil},
+
+		{"all scopes", PermissionBlock{Scopes: []string{"read", "write"}}, &RequirementBlock{Scopes: [][]string{{"read", "write"}}}, true, nil},
+		{"lacking scope", PermissionBlock{Scopes: []string{"read"}}, &RequirementBlock{Scopes: [][]string{{"read", "write"}}}, false, []string{"write"}},
+		{"no scopes", PermissionBlock{}, &RequirementBlock{Scopes: [][]string{{"read"}}}, false, []string{"read"}},
+		{"second alternative", PermissionBlock{Scopes: []string{"admin"}}, &RequirementBlock{Scopes: [][]string{{"read", "write"}, {"admin"}}}, true, nil},
+		{"no alternative reports first", PermissionBlock{Scopes: []string{"read"}}, &RequirementBlock{Scopes: [][]string{{"read", "write"}, {"admin"}}}, false, []string{"write"}},
+		{"scope is case sensitive", PermissionBlock{Scopes: []string{"Read"}}, &RequirementBlock{Scopes: [][]string{{"read"}}}, false, []string{"read"}},
+
+		{"any role", PermissionBlock{Roles: []string{"auditor"}}, &RequirementBlock{Roles: []string{"admin", "auditor"}}, true, nil},
+		{"lacking role", PermissionBlock{Roles: []string{"user"}}, &RequirementBlock{Roles: []string{"admin"}}, false, []string{"admin"}},
+		{"scopes and roles", PermissionBlock{Scopes: []string{"read"}, Roles: []string{"admin"}}, &RequirementBlock{Scopes: [][]string{{"read"}}, Roles: []string{"admin"}}, true, nil},
+		{"scopes without role", PermissionBlock{Scopes: []string{"read"}}, &RequirementBlock{Scopes: [][]string{{"read"}}, Roles: []string{"admin"}}, false, []string{"admin"}},
+		{"role without scopes", PermissionBlock{Roles: []string{"admin"}}, &RequirementBlock{Scopes: [][]string{{"read"}}, Roles: []string{"admin"}}, false, []string{"read"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, missing := tt.permission.Satisfies(tt.requirement)
+			if ok != tt.want {
+				t.Errorf("Satisfies = %v, want %v", ok, tt.want)
+			}
+			if !slices.Equal(missing, tt.missing) {
+				t.Errorf("missing = %v, want %v", missing, tt.missing)
+			}
+		})
+	}
+}
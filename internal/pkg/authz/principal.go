@@ -0,0 +1,33 @@
+package authz
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+const ContextKeyPrincipal = "fiber-boilerplate/#/principal"
+
+// principal 종류
+const (
+	PrincipalAppuser = "appuser"
+	PrincipalAPIKey  = "api_key"
+)
+
+// PrincipalBlock : 요청한 주체.
+// API key 요청은 appuser 세션 없이 key 정보로 만든 synthetic principal 을 가진다
+type PrincipalBlock struct {
+	Kind  string
+	ID    string // appuser uuid 또는 api key uuid
+	Name  string // api key 이름
+	Owner string // api key 소유 서비스
+}
+
+// PrincipalFromContext : 인증되지 않은 요청이면 nil
+func PrincipalFromContext(ctx *fiber.Ctx) *PrincipalBlock {
+	p, _ := ctx.Locals(ContextKeyPrincipal).(*PrincipalBlock)
+	return p
+}
+
+// IsAPIKey :
+func (p *PrincipalBlock) IsAPIKey() bool {
+	return p != nil && p.Kind == PrincipalAPIKey
+}
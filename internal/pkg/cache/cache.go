@@ -2,6 +2,7 @@ package cache
 
 import (
 	"io"
+	"time"
 
 	"fiber-boilerplate/internal/defs"
 )
@@ -9,6 +10,7 @@ import (
 // StoreInterface : 캐쉬 스토어 인터페이스
 type StoreInterface interface {
 	Set(key string, value interface{}) error
+	SetWithTTL(key string, value interface{}, ttl time.Duration) error
 	Lookup(key string) bool
 	Get(key string) (interface{}, bool, error)
 	Reader(key string) (io.Reader, error)
@@ -30,6 +30,12 @@ func (c *MemoryGoCacheBlock) Set(key string, value interface{}) error {
 	return nil
 }
 
+// SetWithTTL : 기본 ttl 대신 key 별 ttl 사용
+func (c *MemoryGoCacheBlock) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	c.provider.Set(key, value, ttl)
+	return nil
+}
+
 // Lookup :
 func (c *MemoryGoCacheBlock) Lookup(key string) (found bool) {
 	_, found = c.provider.Get(key)
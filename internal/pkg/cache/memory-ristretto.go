@@ -47,6 +47,18 @@ func (c *MemoryRistrettoBlock) Set(key string, value interface{}) (err error) {
 	return
 }
 
+// SetWithTTL : 기본 ttl 대신 key 별 ttl 사용
+func (c *MemoryRistrettoBlock) SetWithTTL(key string, value interface{}, ttl time.Duration) (err error) {
+	if c.provider.SetWithTTL(key, value, 1, ttl) {
+		err = nil
+	} else {
+		err = defs.ErrFault
+		logging.Warn(err, "key:%s", key)
+	}
+
+	return
+}
+
 // Lookup :
 func (c *MemoryRistrettoBlock) Lookup(key string) (found bool) {
 	_, found = c.provider.Get(key)
@@ -0,0 +1,197 @@
+package database
+
+import (
+	"context"
+	"sync"
+
+	logging "fiber-boilerplate/internal/pkg/logging"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 구독자의 buffer 가 가득 찼을 때
+const (
+	SlowConsumerDrop       = "drop"       // 그 구독자에게 보낼 메시지를 버림
+	SlowConsumerDisconnect = "disconnect" // 구독을 끝냄. 구독자는 다시 구독
+)
+
+// PubSubConfigBlock : in-process pub/sub 설정
+type PubSubConfigBlock struct {
+	// Buffer : 구독자마다 쌓아 둘 수 있는 메시지 수
+	Buffer int
+	// SlowConsumer : SlowConsumerDrop, SlowConsumerDisconnect
+	SlowConsumer string
+}
+
+var defaultPubSubConfig = PubSubConfigBlock{
+	Buffer:       256,
+	SlowConsumer: SlowConsumerDrop,
+}
+
+// MessageBlock : 채널로 받은 메시지
+type MessageBlock struct {
+	Channel string
+	Payload string
+}
+
+// SubscriptionBlock : SubscribeChannel 로 얻은 구독. Channel 은 Close 하거나 구독이 끝나면 닫힘
+// (느린 구독자로 끊기거나 Redis 와 fallback 사이를 전환할 때). 끝나면 다시 구독
+type SubscriptionBlock struct {
+	messages chan MessageBlock
+	done     chan struct{}
+	once     sync.Once
+	close    func()
+}
+
+func newSubscription(buffer int, close func()) *SubscriptionBlock {
+	return &SubscriptionBlock{
+		messages: make(chan MessageBlock, buffer),
+		done:     make(chan struct{}),
+		close:    close,
+	}
+}
+
+// Channel :
+func (s *SubscriptionBlock) Channel() <-chan MessageBlock {
+	return s.messages
+}
+
+// Close :
+func (s *SubscriptionBlock) Close() error {
+	s.end()
+	return nil
+}
+
+// end : 보내는 쪽이 더 보내지 않도록 done 을 먼저 닫음. messages 는 보내는 쪽이 닫음
+func (s *SubscriptionBlock) end() {
+	s.once.Do(func() {
+		close(s.done)
+		if s.close != nil {
+			s.close()
+		}
+	})
+}
+
+// remoteSubscription : go-redis 구독의 메시지를 옮김. 연결이 끊기면 go-redis 가 다시 구독
+func remoteSubscription(ctx context.Context, cli *redis.Client, channel string) *SubscriptionBlock {
+	pubsub := cli.Subscribe(ctx, channel)
+	s := newSubscription(0, func() { _ = pubsub.Close() })
+	ch := pubsub.Channel()
+
+	go func() {
+		defer close(s.messages)
+		for {
+			select {
+			case <-s.done:
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					s.end()
+					return
+				}
+				select {
+				case s.messages <- MessageBlock{Channel: msg.Channel, Payload: msg.Payload}:
+				case <-s.done:
+					return
+				}
+			}
+		}
+	}()
+
+	return s
+}
+
+// brokerBlock : Redis 가 없을 때의 in-process pub/sub. Redis 처럼 DB 와 관계없이 instance 안에서 하나
+type brokerBlock struct {
+	mu          sync.RWMutex
+	config      PubSubConfigBlock
+	subscribers map[string]map[*SubscriptionBlock]struct{}
+}
+
+var localBroker = &brokerBlock{
+	config:      defaultPubSubConfig,
+	subscribers: map[string]map[*SubscriptionBlock]struct{}{},
+}
+
+// ConfigurePubSub : 이후의 in-process 구독에 적용
+func ConfigurePubSub(config PubSubConfigBlock) {
+	config = pubSubConfig(config)
+	logging.Info("Pub/sub: in-process buffer %d, slow consumer %s", config.Buffer, config.SlowConsumer)
+
+	localBroker.mu.Lock()
+	localBroker.config = config
+	localBroker.mu.Unlock()
+}
+
+// PubSubConfig : ConfigurePubSub 으로 적용된 설정
+func PubSubConfig() PubSubConfigBlock {
+	localBroker.mu.RLock()
+	defer localBroker.mu.RUnlock()
+	return localBroker.config
+}
+
+// pubSubConfig : 비어 있거나 잘못된 값은 기본값
+func pubSubConfig(config PubSubConfigBlock) PubSubConfigBlock {
+	if config.Buffer < 1 {
+		config.Buffer = defaultPubSubConfig.Buffer
+	}
+	switch config.SlowConsumer {
+	case SlowConsumerDrop, SlowConsumerDisconnect:
+	default:
+		if config.SlowConsumer != "" {
+			logging.Warn(nil, "unknown slow consumer policy: %s. using %s", config.SlowConsumer, defaultPubSubConfig.SlowConsumer)
+		}
+		config.SlowConsumer = defaultPubSubConfig.SlowConsumer
+	}
+	return config
+}
+
+func (b *brokerBlock) subscribe(channel string) *SubscriptionBlock {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var s *SubscriptionBlock
+	s = newSubscription(b.config.Buffer, func() { b.remove(channel, s) })
+	if b.subscribers[channel] == nil {
+		b.subscribers[channel] = map[*SubscriptionBlock]struct{}{}
+	}
+	b.subscribers[channel][s] = struct{}{}
+
+	return s
+}
+
+// remove : 구독 목록에서 뺀 뒤 messages 를 닫음. publish 는 목록에 있는 구독에만 보내므로 닫힌 채널에 보내지 않음
+func (b *brokerBlock) remove(channel string, s *SubscriptionBlock) {
+	b.mu.Lock()
+	delete(b.subscribers[channel], s)
+	if len(b.subscribers[channel]) == 0 {
+		delete(b.subscribers, channel)
+	}
+	close(s.messages)
+	b.mu.Unlock()
+}
+
+// publish : 구독자마다 buffer 에 넣음. 가득 찬 구독자는 정책에 따라 버리거나 끊음
+func (b *brokerBlock) publish(channel string, message string) {
+	var slow []*SubscriptionBlock
+
+	b.mu.RLock()
+	msg := MessageBlock{Channel: channel, Payload: message}
+	for s := range b.subscribers[channel] {
+		select {
+		case s.messages <- msg:
+		default:
+			if b.config.SlowConsumer == SlowConsumerDisconnect {
+				slow = append(slow, s)
+			} else {
+				logging.Warn(nil, "pub/sub subscriber is too slow, message dropped: %s", channel)
+			}
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, s := range slow {
+		logging.Warn(nil, "pub/sub subscriber is too slow, disconnected: %s", channel)
+		s.end()
+	}
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+
+	"fiber-boilerplate/internal/defs"
+
+	"github.com/lib/pq"
+)
+
+// SQLSTATE
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	sqlStateStringDataRightTruncation = "22001"
+	sqlStateDatetimeFieldOverflow     = "22008"
+	sqlStateInvalidTextRepresentation = "22P02" // enum, uuid 등 형식이 맞지 않는 값
+	sqlStateNotNullViolation          = "23502"
+	sqlStateForeignKeyViolation       = "23503"
+	sqlStateUniqueViolation           = "23505"
+	sqlStateCheckViolation            = "23514"
+	sqlStateSerializationFailure      = "40001"
+	sqlStateDeadlockDetected          = "40P01"
+)
+
+// Key (uuid)=(...) already exists. -> uuid
+var keyColumnsRegexp = regexp.MustCompile(`^Key \(([^)]+)\)=`)
+
+// TranslateError : sql.ErrNoRows, ErrInvalidOptionClause 와 *pq.Error 를 HTTP status 가 정해진 defs.AppError 로 변환.
+// 해당하지 않는 error 는 그대로 반환하며, 원본 error 는 Cause 로 남아 서버 로그에만 출력된다
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var appErr *defs.AppError
+	if errors.As(err, &appErr) {
+		return err
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return defs.ErrRecordNotFound.Wrap(err)
+	}
+	if errors.Is(err, ErrInvalidOptionClause) {
+		return defs.ErrInvalidParameter.WithMessage("invalid list options").Wrap(err)
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	// pq.Error 의 Message / Detail 에는 입력 값이 들어 있으므로 응답에는 column 이름만 사용
+	switch pqErr.Code {
+	case sqlStateUniqueViolation:
+		return defs.ErrDuplicateRecord.WithViolations(violations(keyColumns(pqErr), "already exists")...).Wrap(err)
+	case sqlStateForeignKeyViolation:
+		return defs.ErrReferenceConflict.WithViolations(violations(keyColumns(pqErr), "reference violated")...).Wrap(err)
+	case sqlStateNotNullViolation:
+		return defs.ErrValidationFailed.WithViolations(violations([]string{pqErr.Column}, "must not be null")...).Wrap(err)
+	case sqlStateCheckViolation:
+		return defs.ErrValidationFailed.WithViolations(violations([]string{pqErr.Column}, "violates constraint "+pqErr.Constraint)...).Wrap(err)
+	case sqlStateInvalidTextRepresentation, sqlStateStringDataRightTruncation, sqlStateDatetimeFieldOverflow:
+		return defs.ErrValidationFailed.WithMessage("invalid value: %s", strings.ReplaceAll(pqErr.Code.Name(), "_", " ")).Wrap(err)
+	case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+		return defs.ErrConcurrentUpdate.Wrap(err)
+	}
+
+	return err
+}
+
+// keyColumns : unique / foreign key 위반의 Detail 에서 column 이름 추출
+func keyColumns(pqErr *pq.Error) []string {
+	matches := keyColumnsRegexp.FindStringSubmatch(pqErr.Detail)
+	if len(matches) < 2 {
+		return []string{pqErr.Column}
+	}
+
+	columns := strings.Split(matches[1], ",")
+	for i := range columns {
+		columns[i] = strings.TrimSpace(columns[i])
+	}
+
+	return columns
+}
+
+// violations : column 을 알 수 없으면 violation 없이 message 만 전달
+func violations(columns []string, message string) (result []defs.Violation) {
+	for _, column := range columns {
+		if column == "" {
+			continue
+		}
+		result = append(result, defs.Violation{Field: column, Message: message})
+	}
+
+	return
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"fiber-boilerplate/internal/defs"
+
+	"github.com/lib/pq"
+)
+
+func TestTranslateError(t *testing.T) {
+	plain := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		err        error
+		want       *defs.AppError // nil 이면 그대로 반환
+		violations []string
+	}{
+		{"no rows", sql.ErrNoRows, defs.ErrRecordNotFound, nil},
+		{"wrapped no rows", fmt.Errorf("get appuser: %w", sql.ErrNoRows), defs.ErrRecordNotFound, nil},
+		{"option clause", ErrInvalidOptionClause, defs.ErrInvalidParameter, nil},
+		{"app error", defs.ErrAppuserNotFound, defs.ErrAppuserNotFound, nil},
+
+		{"unique", &pq.Error{Code: sqlStateUniqueViolation, Detail: "Key (uuid)=(a1b2) already exists."}, defs.ErrDuplicateRecord, []string{"uuid"}},
+		{"unique composite", &pq.Error{Code: sqlStateUniqueViolation, Detail: "Key (appuser_id, name)=(1, kim) already exists."}, defs.ErrDuplicateRecord, []string{"appuser_id", "name"}},
+		{"unique without detail", &pq.Error{Code: sqlStateUniqueViolation}, defs.ErrDuplicateRecord, nil},
+		{"foreign key", &pq.Error{Code: sqlStateForeignKeyViolation, Detail: `Key (appuser_id)=(9) is not present in table "appuser".`}, defs.ErrReferenceConflict, []string{"appuser_id"}},
+		{"not null", &pq.Error{Code: sqlStateNotNullViolation, Column: "name"}, defs.ErrValidationFailed, []string{"name"}},
+		{"check", &pq.Error{Code: sqlStateCheckViolation, Column: "gender", Constraint: "appuser_gender_check"}, defs.ErrValidationFailed, []string{"gender"}},
+		{"invalid text", &pq.Error{Code: sqlStateInvalidTextRepresentation, Message: `invalid input syntax for type uuid: "secret"`}, defs.ErrValidationFailed, nil},
+		{"truncation", &pq.Error{Code: sqlStateStringDataRightTruncation}, defs.ErrValidationFailed, nil},
+		{"datetime overflow", &pq.Error{Code: sqlStateDatetimeFieldOverflow}, defs.ErrValidationFailed, nil},
+		{"serialization", &pq.Error{Code: sqlStateSerializationFailure}, defs.ErrConcurrentUpdate, nil},
+		{"deadlock", fmt.Errorf("update: %w", &pq.Error{Code: sqlStateDeadlockDetected}), defs.ErrConcurrentUpdate, nil},
+
+		{"other pq error", &pq.Error{Code: "42P01"}, nil, nil},
+		{"plain error", plain, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TranslateError(tt.err)
+
+			if tt.want == nil {
+				if got != tt.err {
+					t.Fatalf("TranslateError = %v, want the error unchanged", got)
+				}
+				return
+			}
+
+			var appErr *defs.AppError
+			if !errors.As(got, &appErr) {
+				t.Fatalf("TranslateError = %v, want %s", got, tt.want.Code)
+			}
+			if appErr.Code != tt.want.Code || appErr.Status != tt.want.Status {
+				t.Errorf("TranslateError = %d %s, want %d %s", appErr.Status, appErr.Code, tt.want.Status, tt.want.Code)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("TranslateError = %v, want it to wrap %v", got, tt.err)
+			}
+
+			var fields []string
+			for _, violation := range appErr.Violations {
+				fields = append(fields, violation.Field)
+			}
+			if !slices.Equal(fields, tt.violations) {
+				t.Errorf("violations = %v, want %v", fields, tt.violations)
+			}
+			// 입력 값은 응답에 노출하지 않음
+			if strings.Contains(appErr.Message, "secret") || strings.Contains(appErr.Message, "a1b2") {
+				t.Errorf("message leaks input: %q", appErr.Message)
+			}
+		})
+	}
+
+	if TranslateError(nil) != nil {
+		t.Error("TranslateError(nil) != nil")
+	}
+}
@@ -0,0 +1,187 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"fiber-boilerplate/internal/defs"
+	"fiber-boilerplate/internal/pkg/util"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/go-redsync/redsync/v4"
+	"github.com/google/uuid"
+)
+
+const (
+	lockTries      = 32
+	lockRetryDelay = 50 * time.Millisecond
+)
+
+var (
+	// ErrLockNotAcquired : 다른 곳에서 잠금을 가지고 있어 재시도 안에 얻지 못함
+	ErrLockNotAcquired = defs.NewError("lock not acquired")
+	// ErrLockNotHeld : 만료되었거나 다른 곳에서 다시 얻은 잠금의 연장/해제
+	ErrLockNotHeld = defs.NewError("lock not held")
+)
+
+// LockBlock : Redis.Lock 으로 얻은 잠금
+type LockBlock struct {
+	Name string
+	// Token : fencing token. 같은 이름의 잠금을 얻을 때마다 증가하므로, 잠금으로 보호하는 저장소에
+	// 함께 기록하고 더 작은 token 의 쓰기를 거부하면 만료된 잠금을 가진 쪽의 늦은 쓰기를 막을 수 있음
+	Token int64
+
+	extend func(ctx context.Context) error
+	unlock func(ctx context.Context) error
+}
+
+// Extend : 만료 시간을 처음 ttl 만큼 다시 연장
+func (l *LockBlock) Extend(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return l.extend(ctx)
+}
+
+// Unlock :
+func (l *LockBlock) Unlock(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return l.unlock(ctx)
+}
+
+// notHeld : redsync 는 잠금이 없으면 error 없이 false 를 반환하기도 함
+func notHeld(err error) error {
+	if err == nil {
+		return ErrLockNotHeld
+	}
+	return fmt.Errorf("%w: %v", ErrLockNotHeld, err)
+}
+
+func lockKey(name string) string {
+	return sharedKey(util.String.Concat("lock/", name))
+}
+
+// fenceKey : 만료 없이 유지
+func fenceKey(name string) string {
+	return sharedKey(util.String.Concat("fence/", name))
+}
+
+// redisLock : redsync mutex 를 이름마다 생성
+func redisLock(cli *redis.Client, rs *redsync.Redsync) func(ctx context.Context, name string, ttl time.Duration) (*LockBlock, error) {
+	return func(ctx context.Context, name string, ttl time.Duration) (*LockBlock, error) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		mutex := rs.NewMutex(lockKey(name),
+			redsync.WithExpiry(ttl),
+			redsync.WithTries(lockTries),
+			redsync.WithRetryDelay(lockRetryDelay),
+		)
+		if err := mutex.LockContext(ctx); err != nil {
+			var taken redsync.ErrTaken
+			if errors.Is(err, redsync.ErrFailed) || errors.As(err, &taken) {
+				return nil, fmt.Errorf("%w: %v", ErrLockNotAcquired, err)
+			}
+			return nil, err
+		}
+
+		token, err := cli.Incr(ctx, fenceKey(name)).Result()
+		if err != nil {
+			_, _ = mutex.UnlockContext(context.WithoutCancel(ctx))
+			return nil, err
+		}
+
+		return &LockBlock{
+			Name:  name,
+			Token: token,
+			extend: func(ctx context.Context) error {
+				ok, err := mutex.ExtendContext(ctx)
+				if !ok {
+					return notHeld(err)
+				}
+				return nil
+			},
+			unlock: func(ctx context.Context) error {
+				ok, err := mutex.UnlockContext(ctx)
+				if !ok {
+					return notHeld(err)
+				}
+				return nil
+			},
+		}, nil
+	}
+}
+
+// memoryLock : in-memory fallback. instance 하나 안에서만 유효
+func memoryLock() func(ctx context.Context, name string, ttl time.Duration) (*LockBlock, error) {
+	type heldBlock struct {
+		value     string
+		expiresAt time.Time
+	}
+
+	var mu sync.Mutex
+	held := map[string]heldBlock{}
+	fences := map[string]int64{}
+
+	// owns : mu 를 잡고 호출
+	owns := func(name string, value string) bool {
+		h, ok := held[name]
+		return ok && h.value == value && time.Now().Before(h.expiresAt)
+	}
+
+	return func(ctx context.Context, name string, ttl time.Duration) (*LockBlock, error) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		value := uuid.NewString()
+
+		for try := 0; ; try++ {
+			mu.Lock()
+			if h, ok := held[name]; !ok || !time.Now().Before(h.expiresAt) {
+				held[name] = heldBlock{value: value, expiresAt: time.Now().Add(ttl)}
+				fences[name]++
+				token := fences[name]
+				mu.Unlock()
+
+				return &LockBlock{
+					Name:  name,
+					Token: token,
+					extend: func(ctx context.Context) error {
+						mu.Lock()
+						defer mu.Unlock()
+						if !owns(name, value) {
+							return ErrLockNotHeld
+						}
+						held[name] = heldBlock{value: value, expiresAt: time.Now().Add(ttl)}
+						return nil
+					},
+					unlock: func(ctx context.Context) error {
+						mu.Lock()
+						defer mu.Unlock()
+						if !owns(name, value) {
+							return ErrLockNotHeld
+						}
+						delete(held, name)
+						return nil
+					},
+				}, nil
+			}
+			mu.Unlock()
+
+			if try+1 >= lockTries {
+				return nil, ErrLockNotAcquired
+			}
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("%w: %v", ErrLockNotAcquired, ctx.Err())
+			case <-time.After(lockRetryDelay):
+			}
+		}
+	}
+}
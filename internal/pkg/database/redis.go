@@ -12,6 +12,8 @@ package database
 
 import (
 	"context"
+	"sort"
+	"sync"
 	"time"
 
 	"fiber-boilerplate/internal/pkg/cache"
@@ -27,20 +29,43 @@ import (
 // Redis :
 type Redis struct {
 	Set              func(ctx context.Context, key string, value interface{}) error
+	SetTTL           func(ctx context.Context, key string, value interface{}, ttl time.Duration) error
 	Get              func(ctx context.Context, key string) (interface{}, bool, error)
 	Del              func(ctx context.Context, key string) error
+	SetAdd           func(ctx context.Context, key string, members ...string) error
+	SetRemove        func(ctx context.Context, key string, members ...string) error
+	SetMembers       func(ctx context.Context, key string) ([]string, error)
 	Flush            func(ctx context.Context) error
-	SubscribeChannel func(ctx context.Context, channel string) *redis.PubSub
+	SubscribeChannel func(ctx context.Context, channel string) (*SubscriptionBlock, error)
 	PublishChannel   func(ctx context.Context, channel string, message string) error
-	Close            func() error
+	// MGet : keys 를 한 번에 조회. keys 와 같은 순서로, 없는 key 는 nil
+	MGet func(ctx context.Context, keys ...string) ([]interface{}, error)
+	// StreamAdd : 길이가 maxLen 정도로 제한되고 마지막 추가 후 ttl 이 지나면 사라지는 로그에 추가. 증가하는 ID 반환
+	StreamAdd func(ctx context.Context, key string, value string, maxLen int64, ttl time.Duration) (string, error)
+	// StreamRange : afterID 보다 뒤의 entry 를 최대 count 개, ID 순으로
+	StreamRange func(ctx context.Context, key string, afterID string, count int64) ([]StreamEntryBlock, error)
+	// Lock : 분산 잠금. 얻지 못하면 ctx 가 끝나거나 재시도 횟수를 넘을 때까지 기다림
+	Lock  func(ctx context.Context, name string, ttl time.Duration) (*LockBlock, error)
+	Close func() error
 }
 
 func sharedKey(key string) string {
 	return util.String.Concat("shared/", key)
 }
 
-// NewRedis :
+// NewRedis : Redis 에 연결하지 못하거나 연결이 계속 실패하면 in-memory cache 로 전환하고,
+// 백그라운드에서 다시 연결되면 Redis 로 돌아감 (저장소는 instance 마다 달라질 수 있음)
 func NewRedis(db int, ttlSec int) *Redis {
+	return newRedis(db, ttlSec, false)
+}
+
+// NewStrictRedis : NewRedis 와 같지만 Redis 에 한 번 연결된 뒤에는 in-memory 로 전환하지 않고
+// 장애 동안 ErrRedisUnavailable 반환. 폐기 목록처럼 instance 마다 달라지면 안 되는 데이터
+func NewStrictRedis(db int, ttlSec int) *Redis {
+	return newRedis(db, ttlSec, true)
+}
+
+func newRedis(db int, ttlSec int, strict bool) *Redis {
 	config := driverConfigs[DriverRedis]
 	conn := config.Conn
 
@@ -51,8 +76,6 @@ func NewRedis(db int, ttlSec int) *Redis {
 		}
 	}
 
-	r := new(Redis)
-
 	ttl := time.Duration(ttlSec) * time.Second
 
 	cli := redis.NewClient(&redis.Options{
@@ -84,9 +107,6 @@ func NewRedis(db int, ttlSec int) *Redis {
 				logging.Warn(err, "redis trial:%d", trial)
 				time.Sleep(time.Second)
 			}
-			if trial == 4 {
-				panic(err)
-			}
 		}
 	} else {
 		err = cli.Ping(context.Background()).Err()
@@ -94,139 +114,239 @@ func NewRedis(db int, ttlSec int) *Redis {
 
 	if err == nil {
 		logging.Info("Redis: connected to %s, database %d, ttl %d", conn, db, ttlSec)
+	} else {
+		// 연결될 때까지 in-memory 로 시작
+		logging.Warn(err, "Redis: fallback to in-memory cache, database %d. retrying in background", db)
+	}
+
+	return newBreaker(db, strict, cli, remoteRedis(cli, ttl), memoryRedis(ttlSec), err).redis()
+}
 
-		pool := goredis.NewPool(cli)
-		sync := redsync.New(pool)
-		mutex := sync.NewMutex("fiber-boilerplate-redis-lock")
+// remoteRedis : 읽기/쓰기는 잠그지 않음. 상호 배제가 필요한 곳에서만 Lock 사용
+func remoteRedis(cli *redis.Client, ttl time.Duration) *Redis {
+	r := new(Redis)
 
-		r.Set = func(ctx context.Context, key string, value interface{}) error {
-			if ctx == nil {
-				ctx = context.Background()
-			}
-			err := mutex.LockContext(ctx)
-			if err != nil {
-				return err
-			}
-			defer func() {
-				_, _ = mutex.UnlockContext(ctx)
-			}()
+	r.Lock = redisLock(cli, redsync.New(goredis.NewPool(cli)))
+	redisStream(cli, r)
 
-			sKey := sharedKey(key)
-			return cli.Set(ctx, sKey, value, ttl).Err()
+	r.Set = func(ctx context.Context, key string, value interface{}) error {
+		if ctx == nil {
+			ctx = context.Background()
 		}
+		sKey := sharedKey(key)
+		return cli.Set(ctx, sKey, value, ttl).Err()
+	}
 
-		r.Get = func(ctx context.Context, key string) (interface{}, bool, error) {
-			if ctx == nil {
-				ctx = context.Background()
-			}
-			err := mutex.LockContext(ctx)
-			if err != nil {
-				return nil, false, err
-			}
-			defer func() {
-				_, _ = mutex.UnlockContext(ctx)
-			}()
+	r.SetTTL = func(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		sKey := sharedKey(key)
+		return cli.Set(ctx, sKey, value, ttl).Err()
+	}
 
-			sKey := sharedKey(key)
-			value, err := cli.Get(ctx, sKey).Bytes()
-			switch err {
-			case redis.Nil:
-				return nil, false, nil
-			case nil:
-				return value, true, nil
-			default:
-				return nil, false, err
-			}
+	r.Get = func(ctx context.Context, key string) (interface{}, bool, error) {
+		if ctx == nil {
+			ctx = context.Background()
 		}
+		sKey := sharedKey(key)
+		value, err := cli.Get(ctx, sKey).Bytes()
+		switch err {
+		case redis.Nil:
+			return nil, false, nil
+		case nil:
+			return value, true, nil
+		default:
+			return nil, false, err
+		}
+	}
 
-		r.Del = func(ctx context.Context, key string) error {
-			if ctx == nil {
-				ctx = context.Background()
-			}
-			err := mutex.LockContext(ctx)
-			if err != nil {
-				return err
+	r.MGet = func(ctx context.Context, keys ...string) ([]interface{}, error) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		sKeys := make([]string, 0, len(keys))
+		for _, key := range keys {
+			sKeys = append(sKeys, sharedKey(key))
+		}
+		values, err := cli.MGet(ctx, sKeys...).Result()
+		if err != nil {
+			return nil, err
+		}
+		// Get 과 같이 []byte 로 반환
+		for i, value := range values {
+			if str, ok := value.(string); ok {
+				values[i] = []byte(str)
 			}
-			defer func() {
-				_, _ = mutex.UnlockContext(ctx)
-			}()
-
-			sKey := sharedKey(key)
-			return cli.Del(ctx, sKey).Err()
 		}
+		return values, nil
+	}
 
-		r.Flush = func(ctx context.Context) error {
-			if ctx == nil {
-				ctx = context.Background()
-			}
-			err := mutex.LockContext(ctx)
-			if err != nil {
-				return err
-			}
-			defer func() {
-				_, _ = mutex.UnlockContext(ctx)
-			}()
+	r.Del = func(ctx context.Context, key string) error {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		sKey := sharedKey(key)
+		return cli.Del(ctx, sKey).Err()
+	}
 
-			return cli.FlushDB(ctx).Err()
+	// SetAdd : set 의 ttl 은 추가할 때마다 갱신
+	r.SetAdd = func(ctx context.Context, key string, members ...string) error {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		sKey := sharedKey(key)
+		values := make([]interface{}, len(members))
+		for i, member := range members {
+			values[i] = member
 		}
+		_, err := cli.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SAdd(ctx, sKey, values...)
+			pipe.Expire(ctx, sKey, ttl)
+			return nil
+		})
+		return err
+	}
 
-		r.SubscribeChannel = func(ctx context.Context, channel string) *redis.PubSub {
-			if ctx == nil {
-				ctx = context.Background()
-			}
-			err := mutex.LockContext(ctx)
-			if err != nil {
-				return nil
-			}
-			defer func() {
-				_, _ = mutex.UnlockContext(ctx)
-			}()
+	r.SetRemove = func(ctx context.Context, key string, members ...string) error {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		sKey := sharedKey(key)
+		values := make([]interface{}, len(members))
+		for i, member := range members {
+			values[i] = member
+		}
+		return cli.SRem(ctx, sKey, values...).Err()
+	}
 
-			return cli.Subscribe(ctx, channel)
+	r.SetMembers = func(ctx context.Context, key string) ([]string, error) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		sKey := sharedKey(key)
+		members, err := cli.SMembers(ctx, sKey).Result()
+		if err != nil {
+			return nil, err
 		}
+		sort.Strings(members)
+		return members, nil
+	}
 
-		r.PublishChannel = func(ctx context.Context, channel string, message string) error {
-			if ctx == nil {
-				ctx = context.Background()
-			}
-			err := mutex.LockContext(ctx)
-			if err != nil {
-				return err
-			}
-			defer func() {
-				_, _ = mutex.UnlockContext(ctx)
-			}()
+	r.Flush = func(ctx context.Context) error {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		return cli.FlushDB(ctx).Err()
+	}
 
-			return cli.Publish(ctx, channel, message).Err()
+	r.SubscribeChannel = func(ctx context.Context, channel string) (*SubscriptionBlock, error) {
+		if ctx == nil {
+			ctx = context.Background()
 		}
+		return remoteSubscription(ctx, cli, channel), nil
+	}
 
-		r.Close = func() error {
-			logging.Info("Redis: closing connection, database %d (this should not happen during normal operation)", db)
-			return cli.Close()
+	r.PublishChannel = func(ctx context.Context, channel string, message string) error {
+		if ctx == nil {
+			ctx = context.Background()
 		}
+		return cli.Publish(ctx, channel, message).Err()
+	}
 
-	} else {
-		logging.Warn(err, "Redis: fallback to in-memory cache")
+	r.Close = cli.Close
 
-		fallback := cache.New(cache.StoreMemoryDefault, ttlSec)
+	return r
+}
 
-		r.Set = func(ctx context.Context, key string, value interface{}) error {
-			return fallback.Set(key, value)
+// memoryRedis : in-memory fallback. instance 하나 안에서만 유효
+func memoryRedis(ttlSec int) *Redis {
+	r := new(Redis)
+
+	fallback := cache.New(cache.StoreMemoryDefault, ttlSec)
+
+	r.Set = func(ctx context.Context, key string, value interface{}) error {
+		return fallback.Set(key, value)
+	}
+	r.SetTTL = func(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+		return fallback.SetWithTTL(key, value, ttl)
+	}
+	r.Get = func(ctx context.Context, key string) (interface{}, bool, error) {
+		return fallback.Get(key)
+	}
+	r.MGet = func(ctx context.Context, keys ...string) ([]interface{}, error) {
+		values := make([]interface{}, len(keys))
+		for i, key := range keys {
+			value, found, err := fallback.Get(key)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				values[i] = value
+			}
 		}
-		r.Get = func(ctx context.Context, key string) (interface{}, bool, error) {
-			return fallback.Get(key)
+		return values, nil
+	}
+	r.Del = func(ctx context.Context, key string) error {
+		return fallback.Del(key)
+	}
+	// set 은 map 으로 저장하고, 읽고 고쳐 쓰는 동안 setMu 로 보호
+	var setMu sync.Mutex
+	fallbackSet := func(key string) map[string]struct{} {
+		if value, found, _ := fallback.Get(key); found {
+			if set, ok := value.(map[string]struct{}); ok {
+				return set
+			}
+		}
+		return map[string]struct{}{}
+	}
+	r.SetAdd = func(ctx context.Context, key string, members ...string) error {
+		setMu.Lock()
+		defer setMu.Unlock()
+		set := fallbackSet(key)
+		for _, member := range members {
+			set[member] = struct{}{}
+		}
+		return fallback.Set(key, set)
+	}
+	r.SetRemove = func(ctx context.Context, key string, members ...string) error {
+		setMu.Lock()
+		defer setMu.Unlock()
+		set := fallbackSet(key)
+		for _, member := range members {
+			delete(set, member)
 		}
-		r.Del = func(ctx context.Context, key string) error {
+		if len(set) == 0 {
 			return fallback.Del(key)
 		}
-		r.Flush = func(ctx context.Context) error {
-			return fallback.Clear()
+		return fallback.Set(key, set)
+	}
+	r.SetMembers = func(ctx context.Context, key string) ([]string, error) {
+		setMu.Lock()
+		defer setMu.Unlock()
+		set := fallbackSet(key)
+		members := make([]string, 0, len(set))
+		for member := range set {
+			members = append(members, member)
 		}
-		// do nothing
-		r.SubscribeChannel = nil
-		r.PublishChannel = nil
-		r.Close = func() error { return nil }
+		sort.Strings(members)
+		return members, nil
+	}
+	r.Flush = func(ctx context.Context) error {
+		return fallback.Clear()
+	}
+	r.Lock = memoryLock()
+	// 로그는 instance 마다 따로 유지
+	memoryStream(r)
+	// 같은 instance 안에서만 메시지를 주고받음
+	r.SubscribeChannel = func(ctx context.Context, channel string) (*SubscriptionBlock, error) {
+		return localBroker.subscribe(channel), nil
+	}
+	r.PublishChannel = func(ctx context.Context, channel string, message string) error {
+		localBroker.publish(channel, message)
+		return nil
 	}
+	r.Close = func() error { return nil }
 
 	return r
 }
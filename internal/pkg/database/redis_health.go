@@ -0,0 +1,403 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"fiber-boilerplate/internal/defs"
+	logging "fiber-boilerplate/internal/pkg/logging"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Redis 연결 상태
+const (
+	RedisStateUp       = "up"       // Redis 사용
+	RedisStateDegraded = "degraded" // in-memory fallback 사용
+	RedisStateDown     = "down"     // strict 저장소. ErrRedisUnavailable 반환
+)
+
+const (
+	// breakerFailureThreshold : 연속으로 이만큼 실패하면 fallback 으로 전환
+	breakerFailureThreshold = 3
+	probeIntervalMin        = time.Second
+	probeIntervalMax        = 30 * time.Second
+	probeTimeout            = 2 * time.Second
+)
+
+// ErrRedisUnavailable : strict 저장소의 Redis 장애
+var ErrRedisUnavailable = defs.NewError("redis unavailable")
+
+// RedisHealthBlock : NewRedis 로 만든 연결 하나의 상태
+type RedisHealthBlock struct {
+	DB     int
+	State  string
+	Strict bool
+	// Since : State 가 바뀐 시각
+	Since time.Time
+	// Failures : 연속 실패 횟수 (fallback 중에는 재연결 시도 실패 포함)
+	Failures  int
+	LastError string
+}
+
+var (
+	breakers   []*breakerBlock
+	breakersMu sync.Mutex
+)
+
+// RedisHealth : 연결마다 상태 (DB 순)
+func RedisHealth() []RedisHealthBlock {
+	breakersMu.Lock()
+	list := append([]*breakerBlock{}, breakers...)
+	breakersMu.Unlock()
+
+	health := make([]RedisHealthBlock, 0, len(list))
+	for _, b := range list {
+		health = append(health, b.health())
+	}
+	sort.SliceStable(health, func(i, j int) bool {
+		return health[i].DB < health[j].DB
+	})
+
+	return health
+}
+
+// breakerBlock : Redis 와 in-memory fallback 사이의 circuit breaker.
+// Redis 명령이 연속으로 실패하면 열리고(fallback), 백그라운드 ping 이 성공하면 닫힘(Redis)
+type breakerBlock struct {
+	db     int
+	strict bool
+	cli    *redis.Client
+	remote *Redis
+	memory *Redis
+
+	mu       sync.Mutex
+	up       bool
+	everUp   bool
+	since    time.Time
+	failures int
+	lastErr  error
+	probing  bool
+	stop     chan struct{}
+	// changed : Redis 와 fallback 사이를 전환할 때마다 닫고 새로 만듦
+	changed chan struct{}
+}
+
+func newBreaker(db int, strict bool, cli *redis.Client, remote *Redis, memory *Redis, err error) *breakerBlock {
+	b := &breakerBlock{
+		db:      db,
+		strict:  strict,
+		cli:     cli,
+		remote:  remote,
+		memory:  memory,
+		up:      err == nil,
+		everUp:  err == nil,
+		since:   time.Now(),
+		lastErr: err,
+		stop:    make(chan struct{}),
+		changed: make(chan struct{}),
+	}
+	if !b.up {
+		b.failures = 1
+		b.probing = true
+		go b.probe()
+	}
+
+	breakersMu.Lock()
+	breakers = append(breakers, b)
+	breakersMu.Unlock()
+
+	return b
+}
+
+func (b *breakerBlock) health() RedisHealthBlock {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	health := RedisHealthBlock{
+		DB:       b.db,
+		State:    RedisStateUp,
+		Strict:   b.strict,
+		Since:    b.since,
+		Failures: b.failures,
+	}
+	if !b.up {
+		health.State = RedisStateDegraded
+		if b.strict && b.everUp {
+			health.State = RedisStateDown
+		}
+	}
+	if b.lastErr != nil {
+		health.LastError = b.lastErr.Error()
+	}
+
+	return health
+}
+
+// backend : 사용할 저장소. strict 저장소의 장애 중에는 nil
+func (b *breakerBlock) backend() (backend *Redis, remote bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.up:
+		return b.remote, true
+	case b.strict && b.everUp:
+		return nil, false
+	default:
+		return b.memory, false
+	}
+}
+
+// subscribeBackend : backend 와 그 backend 를 쓰는 동안 열려 있는 채널
+func (b *breakerBlock) subscribeBackend() (*Redis, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.up:
+		return b.remote, b.changed
+	case b.strict && b.everUp:
+		return nil, b.changed
+	default:
+		return b.memory, b.changed
+	}
+}
+
+// switched : b.mu 를 잡고 호출
+func (b *breakerBlock) switched() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// report : Redis 명령 결과. 서버가 응답한 error (WRONGTYPE 등) 와 취소는 장애로 보지 않음
+func (b *breakerBlock) report(err error) {
+	var redisErr redis.Error
+	if err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, ErrLockNotAcquired) || errors.Is(err, ErrLockNotHeld) || errors.Is(err, ErrInvalidStreamID) || errors.As(err, &redisErr)) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	b.lastErr = err
+	if !b.up || b.failures < breakerFailureThreshold {
+		return
+	}
+
+	b.up = false
+	b.since = time.Now()
+	b.switched()
+	if b.strict {
+		logging.Error(err, "Redis: database %d unavailable after %d failures. rejecting until reconnected", b.db, b.failures)
+	} else {
+		logging.Warn(err, "Redis: database %d fallback to in-memory cache after %d failures", b.db, b.failures)
+	}
+	if !b.probing {
+		b.probing = true
+		go b.probe()
+	}
+}
+
+// probe : Redis 가 응답할 때까지 간격을 늘려 가며 ping (half-open)
+func (b *breakerBlock) probe() {
+	interval := probeIntervalMin
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-time.After(interval):
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+		err := b.cli.Ping(ctx).Err()
+		cancel()
+
+		b.mu.Lock()
+		if err == nil {
+			b.up = true
+			b.everUp = true
+			b.since = time.Now()
+			b.failures = 0
+			b.lastErr = nil
+			b.probing = false
+			b.switched()
+			b.mu.Unlock()
+			// fallback 에 쓴 값은 옮기지 않음
+			logging.Info("Redis: database %d reconnected", b.db)
+			return
+		}
+		b.failures++
+		b.lastErr = err
+		b.mu.Unlock()
+
+		interval = min(interval*2, probeIntervalMax)
+	}
+}
+
+// do : 고른 저장소로 실행하고 Redis 결과는 breaker 에 반영
+func (b *breakerBlock) do(fn func(backend *Redis) error) error {
+	backend, remote := b.backend()
+	if backend == nil {
+		return ErrRedisUnavailable
+	}
+
+	err := fn(backend)
+	if remote {
+		b.report(err)
+	}
+
+	return err
+}
+
+// redis : 상태에 따라 Redis 또는 fallback 으로 보내는 Redis
+func (b *breakerBlock) redis() *Redis {
+	r := new(Redis)
+
+	r.Set = func(ctx context.Context, key string, value interface{}) error {
+		return b.do(func(backend *Redis) error {
+			return backend.Set(ctx, key, value)
+		})
+	}
+	r.SetTTL = func(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+		return b.do(func(backend *Redis) error {
+			return backend.SetTTL(ctx, key, value, ttl)
+		})
+	}
+	r.Get = func(ctx context.Context, key string) (value interface{}, found bool, err error) {
+		err = b.do(func(backend *Redis) (err error) {
+			value, found, err = backend.Get(ctx, key)
+			return
+		})
+		return
+	}
+	r.MGet = func(ctx context.Context, keys ...string) (values []interface{}, err error) {
+		err = b.do(func(backend *Redis) (err error) {
+			values, err = backend.MGet(ctx, keys...)
+			return
+		})
+		return
+	}
+	r.Del = func(ctx context.Context, key string) error {
+		return b.do(func(backend *Redis) error {
+			return backend.Del(ctx, key)
+		})
+	}
+	r.SetAdd = func(ctx context.Context, key string, members ...string) error {
+		return b.do(func(backend *Redis) error {
+			return backend.SetAdd(ctx, key, members...)
+		})
+	}
+	r.SetRemove = func(ctx context.Context, key string, members ...string) error {
+		return b.do(func(backend *Redis) error {
+			return backend.SetRemove(ctx, key, members...)
+		})
+	}
+	r.SetMembers = func(ctx context.Context, key string) (members []string, err error) {
+		err = b.do(func(backend *Redis) (err error) {
+			members, err = backend.SetMembers(ctx, key)
+			return
+		})
+		return
+	}
+	r.Flush = func(ctx context.Context) error {
+		return b.do(func(backend *Redis) error {
+			return backend.Flush(ctx)
+		})
+	}
+	// SubscribeChannel : Redis 와 fallback 사이를 전환하면 구독을 끝내 구독자가 새 backend 로 다시 구독하게 함
+	r.SubscribeChannel = func(ctx context.Context, channel string) (*SubscriptionBlock, error) {
+		backend, changed := b.subscribeBackend()
+		if backend == nil {
+			return nil, ErrRedisUnavailable
+		}
+		inner, err := backend.SubscribeChannel(ctx, channel)
+		if err != nil {
+			return nil, err
+		}
+
+		s := newSubscription(0, func() { _ = inner.Close() })
+		go func() {
+			defer close(s.messages)
+			for {
+				select {
+				case <-s.done:
+					return
+				case <-changed:
+					s.end()
+					return
+				case msg, ok := <-inner.Channel():
+					if !ok {
+						s.end()
+						return
+					}
+					select {
+					case s.messages <- msg:
+					case <-s.done:
+						return
+					}
+				}
+			}
+		}()
+
+		return s, nil
+	}
+	r.PublishChannel = func(ctx context.Context, channel string, message string) error {
+		return b.do(func(backend *Redis) error {
+			return backend.PublishChannel(ctx, channel, message)
+		})
+	}
+	r.StreamAdd = func(ctx context.Context, key string, value string, maxLen int64, ttl time.Duration) (id string, err error) {
+		err = b.do(func(backend *Redis) (err error) {
+			id, err = backend.StreamAdd(ctx, key, value, maxLen, ttl)
+			return
+		})
+		return
+	}
+	r.StreamRange = func(ctx context.Context, key string, afterID string, count int64) (entries []StreamEntryBlock, err error) {
+		err = b.do(func(backend *Redis) (err error) {
+			entries, err = backend.StreamRange(ctx, key, afterID, count)
+			return
+		})
+		return
+	}
+	r.Lock = func(ctx context.Context, name string, ttl time.Duration) (lock *LockBlock, err error) {
+		err = b.do(func(backend *Redis) (err error) {
+			lock, err = backend.Lock(ctx, name, ttl)
+			return
+		})
+		return
+	}
+	r.Close = func() error {
+		logging.Info("Redis: closing connection, database %d (this should not happen during normal operation)", b.db)
+		b.mu.Lock()
+		select {
+		case <-b.stop:
+		default:
+			close(b.stop)
+		}
+		b.mu.Unlock()
+
+		breakersMu.Lock()
+		for i, other := range breakers {
+			if other == b {
+				breakers = append(breakers[:i], breakers[i+1:]...)
+				break
+			}
+		}
+		breakersMu.Unlock()
+
+		return b.cli.Close()
+	}
+
+	return r
+}
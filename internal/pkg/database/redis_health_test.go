@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// serverError : 서버가 응답한 error (redis.Error)
+type serverError string
+
+func (e serverError) Error() string { return string(e) }
+func (serverError) RedisError()     {}
+
+func TestBreakerReport(t *testing.T) {
+	down := errors.New("dial tcp: connection refused")
+
+	tests := []struct {
+		name     string
+		strict   bool
+		errs     []error
+		state    string
+		failures int
+	}{
+		{"success", false, []error{nil, nil}, RedisStateUp, 0},
+		{"below threshold", false, []error{down, down}, RedisStateUp, 2},
+		{"success resets", false, []error{down, down, nil, down, down}, RedisStateUp, 2},
+		{"threshold", false, []error{down, down, down}, RedisStateDegraded, 3},
+		{"strict threshold", true, []error{down, down, down}, RedisStateDown, 3},
+		{"wrapped", false, []error{fmt.Errorf("get: %w", down), down, down}, RedisStateDegraded, 3},
+
+		{"server error", false, []error{serverError("WRONGTYPE"), serverError("WRONGTYPE"), serverError("WRONGTYPE")}, RedisStateUp, 0},
+		{"canceled", false, []error{context.Canceled, context.Canceled, context.Canceled}, RedisStateUp, 0},
+		{"lock not acquired", false, []error{ErrLockNotAcquired, ErrLockNotHeld, ErrLockNotAcquired}, RedisStateUp, 0},
+		{"invalid stream id", false, []error{ErrInvalidStreamID, ErrInvalidStreamID, ErrInvalidStreamID}, RedisStateUp, 0},
+		{"ignored between failures", false, []error{down, serverError("WRONGTYPE"), down}, RedisStateUp, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// probing 을 켜 두어 열려도 probe goroutine 을 시작하지 않음
+			b := &breakerBlock{strict: tt.strict, up: true, everUp: true, probing: true, stop: make(chan struct{}), changed: make(chan struct{})}
+			changed := b.changed
+
+			for _, err := range tt.errs {
+				b.report(err)
+			}
+
+			health := b.health()
+			if health.State != tt.state || health.Failures != tt.failures {
+				t.Errorf("state = %s, failures = %d, want %s, %d", health.State, health.Failures, tt.state, tt.failures)
+			}
+			select {
+			case <-changed:
+				if tt.state == RedisStateUp {
+					t.Error("changed closed while up")
+				}
+			default:
+				if tt.state != RedisStateUp {
+					t.Error("changed not closed on fallback")
+				}
+			}
+		})
+	}
+}
+
+// TestBreakerFailover : Redis 가 멈추면 fallback (strict 는 ErrRedisUnavailable), 다시 뜨면 Redis 로 돌아옴
+func TestBreakerFailover(t *testing.T) {
+	mr := miniredis.RunT(t)
+	driverConfigs[DriverRedis] = DriverConfigBlock{Conn: mr.Addr()}
+
+	r := NewRedis(0, 60)
+	strict := NewStrictRedis(1, 60)
+	t.Cleanup(func() {
+		_ = r.Close()
+		_ = strict.Close()
+	})
+	ctx := context.Background()
+
+	if err := r.Set(ctx, "key", []byte("remote")); err != nil {
+		t.Fatal(err)
+	}
+
+	mr.Close()
+	for i := 0; i < breakerFailureThreshold; i++ {
+		_, _, _ = r.Get(ctx, "key")
+		_, _, _ = strict.Get(ctx, "key")
+	}
+
+	// fallback 은 Redis 의 값을 가지고 있지 않음
+	if _, found, err := r.Get(ctx, "key"); err != nil || found {
+		t.Errorf("fallback Get found = %v, err = %v", found, err)
+	}
+	if err := r.Set(ctx, "key", []byte("memory")); err != nil {
+		t.Errorf("fallback Set err = %v", err)
+	}
+	if _, _, err := strict.Get(ctx, "key"); !errors.Is(err, ErrRedisUnavailable) {
+		t.Errorf("strict Get err = %v, want %v", err, ErrRedisUnavailable)
+	}
+
+	if err := mr.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		value, found, err := r.Get(ctx, "key")
+		_, _, strictErr := strict.Get(ctx, "key")
+		if err == nil && found && string(value.([]byte)) == "remote" && strictErr == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("not reconnected: value = %v, found = %v, err = %v, strict err = %v", value, found, err, strictErr)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
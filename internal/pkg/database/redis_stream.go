@@ -0,0 +1,226 @@
+package database
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fiber-boilerplate/internal/defs"
+	"fiber-boilerplate/internal/pkg/util"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// streamField : stream entry 의 값을 넣는 field
+const streamField = "v"
+
+// ErrInvalidStreamID :
+var ErrInvalidStreamID = defs.NewError("invalid stream id")
+
+// StreamEntryBlock : stream 에 추가한 값과 그 ID ("<unix milli>-<seq>")
+type StreamEntryBlock struct {
+	ID    string
+	Value string
+}
+
+// ParseStreamID : "<unix milli>-<seq>"
+func ParseStreamID(id string) (ms uint64, seq uint64, err error) {
+	msText, seqText, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, ErrInvalidStreamID
+	}
+	if ms, err = strconv.ParseUint(msText, 10, 64); err != nil {
+		return 0, 0, ErrInvalidStreamID
+	}
+	if seq, err = strconv.ParseUint(seqText, 10, 64); err != nil {
+		return 0, 0, ErrInvalidStreamID
+	}
+	return ms, seq, nil
+}
+
+// CompareStreamID : a < b 이면 -1, 같으면 0, a > b 이면 1. 형식이 맞지 않는 ID 는 가장 작음
+func CompareStreamID(a, b string) int {
+	aMs, aSeq, aErr := ParseStreamID(a)
+	bMs, bSeq, bErr := ParseStreamID(b)
+	switch {
+	case aErr != nil && bErr != nil:
+		return 0
+	case aErr != nil:
+		return -1
+	case bErr != nil:
+		return 1
+	case aMs != bMs:
+		return compareUint(aMs, bMs)
+	default:
+		return compareUint(aSeq, bSeq)
+	}
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// redisStream : Redis Streams. 추가할 때마다 길이를 maxLen 근처로 자르고 key 의 ttl 갱신
+func redisStream(cli *redis.Client, r *Redis) {
+	r.StreamAdd = func(ctx context.Context, key string, value string, maxLen int64, ttl time.Duration) (string, error) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		sKey := sharedKey(key)
+		var add *redis.StringCmd
+		_, err := cli.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			add = pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: sKey,
+				MaxLen: maxLen,
+				Approx: true,
+				Values: []interface{}{streamField, value},
+			})
+			pipe.Expire(ctx, sKey, ttl)
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+		return add.Val(), nil
+	}
+
+	// StreamRange : afterID 다음부터. "(" 범위는 Redis 6.2 부터라 afterID 를 포함해 읽고 건너뜀
+	r.StreamRange = func(ctx context.Context, key string, afterID string, count int64) ([]StreamEntryBlock, error) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if _, _, err := ParseStreamID(afterID); err != nil {
+			return nil, err
+		}
+		sKey := sharedKey(key)
+		messages, err := cli.XRangeN(ctx, sKey, afterID, "+", count+1).Result()
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]StreamEntryBlock, 0, len(messages))
+		for _, message := range messages {
+			if message.ID == afterID {
+				continue
+			}
+			value, _ := message.Values[streamField].(string)
+			entries = append(entries, StreamEntryBlock{ID: message.ID, Value: value})
+		}
+		if int64(len(entries)) > count {
+			entries = entries[:count]
+		}
+		return entries, nil
+	}
+}
+
+// ringBlock : stream 하나의 in-memory 대체. 처음 추가할 때의 maxLen 크기로 만들고 가득 차면 가장 오래된 entry 를 덮어씀
+type ringBlock struct {
+	entries  []StreamEntryBlock
+	start    int
+	size     int
+	expireAt time.Time
+}
+
+func (r *ringBlock) add(entry StreamEntryBlock) {
+	end := (r.start + r.size) % len(r.entries)
+	r.entries[end] = entry
+	if r.size < len(r.entries) {
+		r.size++
+	} else {
+		r.start = (r.start + 1) % len(r.entries)
+	}
+}
+
+func (r *ringBlock) list() []StreamEntryBlock {
+	list := make([]StreamEntryBlock, 0, r.size)
+	for i := 0; i < r.size; i++ {
+		list = append(list, r.entries[(r.start+i)%len(r.entries)])
+	}
+	return list
+}
+
+// memoryStream : ring buffer. ID 는 Redis 와 같은 형식으로 instance 안에서 증가
+func memoryStream(r *Redis) {
+	var (
+		mu        sync.Mutex
+		rings     = map[string]*ringBlock{}
+		lastMs    uint64
+		lastSeq   uint64
+		lastSweep time.Time
+	)
+
+	// sweep : 만료된 stream 정리 (mu 를 잡고 호출)
+	sweep := func(now time.Time) {
+		if now.Sub(lastSweep) < time.Minute {
+			return
+		}
+		lastSweep = now
+		for key, ring := range rings {
+			if now.After(ring.expireAt) {
+				delete(rings, key)
+			}
+		}
+	}
+
+	r.StreamAdd = func(ctx context.Context, key string, value string, maxLen int64, ttl time.Duration) (string, error) {
+		if maxLen < 1 {
+			maxLen = 1
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		sweep(now)
+
+		ms := uint64(now.UnixMilli())
+		if ms <= lastMs {
+			lastSeq++
+		} else {
+			lastMs, lastSeq = ms, 0
+		}
+		id := util.String.Concat(strconv.FormatUint(lastMs, 10), "-", strconv.FormatUint(lastSeq, 10))
+
+		ring := rings[key]
+		if ring == nil || now.After(ring.expireAt) {
+			ring = &ringBlock{entries: make([]StreamEntryBlock, maxLen)}
+			rings[key] = ring
+		}
+		ring.add(StreamEntryBlock{ID: id, Value: value})
+		ring.expireAt = now.Add(ttl)
+
+		return id, nil
+	}
+
+	r.StreamRange = func(ctx context.Context, key string, afterID string, count int64) ([]StreamEntryBlock, error) {
+		if _, _, err := ParseStreamID(afterID); err != nil {
+			return nil, err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		ring := rings[key]
+		if ring == nil || time.Now().After(ring.expireAt) {
+			return []StreamEntryBlock{}, nil
+		}
+		entries := []StreamEntryBlock{}
+		for _, entry := range ring.list() {
+			if int64(len(entries)) >= count {
+				break
+			}
+			if CompareStreamID(entry.ID, afterID) > 0 {
+				entries = append(entries, entry)
+			}
+		}
+		return entries, nil
+	}
+}
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCompareStreamID(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1-0", "1-0", 0},
+		{"1-0", "1-1", -1},
+		{"1-10", "1-9", 1},
+		{"2-0", "10-0", -1},
+		{"18446744073709551615-0", "1-0", 1},
+		{"bad", "0-0", -1},
+		{"0-0", "bad", 1},
+		{"bad", "worse", 0},
+	}
+
+	for _, tt := range tests {
+		if got := CompareStreamID(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareStreamID(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseStreamID(t *testing.T) {
+	for _, id := range []string{"", "1", "1-", "-1", "a-1", "1-b", "1-2-3", "-1-0", "18446744073709551616-0"} {
+		if _, _, err := ParseStreamID(id); !errors.Is(err, ErrInvalidStreamID) {
+			t.Errorf("ParseStreamID(%q) err = %v, want %v", id, err, ErrInvalidStreamID)
+		}
+	}
+	if ms, seq, err := ParseStreamID("1700000000000-3"); err != nil || ms != 1700000000000 || seq != 3 {
+		t.Errorf("ParseStreamID = %d, %d, %v", ms, seq, err)
+	}
+}
+
+func TestRingBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		adds     int
+		want     []string
+	}{
+		{"empty", 3, 0, []string{}},
+		{"partial", 3, 2, []string{"0", "1"}},
+		{"full", 3, 3, []string{"0", "1", "2"}},
+		{"wrapped", 3, 5, []string{"2", "3", "4"}},
+		{"wrapped twice", 3, 7, []string{"4", "5", "6"}},
+		{"single", 1, 4, []string{"3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring := &ringBlock{entries: make([]StreamEntryBlock, tt.capacity)}
+			for i := 0; i < tt.adds; i++ {
+				ring.add(StreamEntryBlock{ID: strconv.Itoa(i)})
+			}
+
+			got := []string{}
+			for _, entry := range ring.list() {
+				got = append(got, entry.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("list = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStream(t *testing.T) {
+	r := new(Redis)
+	memoryStream(r)
+	ctx := context.Background()
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		id, err := r.StreamAdd(ctx, "topic", strconv.Itoa(i), 3, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) > 0 && CompareStreamID(id, ids[len(ids)-1]) <= 0 {
+			t.Fatalf("id %s is not after %s", id, ids[len(ids)-1])
+		}
+		ids = append(ids, id)
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		afterID string
+		count   int64
+		want    []string
+		wantErr error
+	}{
+		{"from start keeps maxLen", "topic", "0-0", 10, []string{"2", "3", "4"}, nil},
+		{"after id", "topic", ids[3], 10, []string{"4"}, nil},
+		{"after last", "topic", ids[4], 10, []string{}, nil},
+		{"count", "topic", "0-0", 2, []string{"2", "3"}, nil},
+		{"after evicted id", "topic", ids[0], 10, []string{"2", "3", "4"}, nil},
+		{"unknown key", "other", "0-0", 10, []string{}, nil},
+		{"invalid id", "topic", "latest", 10, nil, ErrInvalidStreamID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := r.StreamRange(ctx, tt.key, tt.afterID, tt.count)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got := []string{}
+			for _, entry := range entries {
+				got = append(got, entry.Value)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("values = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"fiber-boilerplate/internal/defs"
 	logging "fiber-boilerplate/internal/pkg/logging"
 	"fiber-boilerplate/internal/pkg/setting"
 	"fiber-boilerplate/internal/pkg/util"
@@ -24,6 +25,16 @@ const (
 	socketDir = "/cloudsql"
 )
 
+var (
+	optionParamRegexp = regexp.MustCompile(`\$(\d+)`)
+	// ? 1 = @options::text 자리에 들어갈 수 있는 형태
+	// ORDER BY <ident> <ASC|DESC>[, ...] [LIMIT <n> [OFFSET <n>]]
+	optionClauseRegexp = regexp.MustCompile(`^(ORDER BY [a-z_][a-z0-9_]* (ASC|DESC)(, [a-z_][a-z0-9_]* (ASC|DESC))*)? ?(LIMIT [0-9]+( OFFSET [0-9]+)?)?$`)
+)
+
+// ErrInvalidOptionClause : 허용되지 않는 ORDER BY / LIMIT 절
+var ErrInvalidOptionClause = defs.NewError("invalid list option clause")
+
 // SQL :
 type SQL struct {
 	db *sqlx.DB
@@ -62,7 +73,12 @@ func (x *SQL) QueryRowContext(ctx context.Context, query string, args ...interfa
 
 // BeginxContext : Begin a transaction with context propagation
 func (x *SQL) BeginxContext(ctx context.Context) (*SQLTX, context.Context, error) {
-	tx, err := x.db.BeginTx(ctx, nil)
+	return x.BeginTxContext(ctx, nil)
+}
+
+// BeginTxContext : Begin a transaction with isolation level / read-only options
+func (x *SQL) BeginTxContext(ctx context.Context, opts *sql.TxOptions) (*SQLTX, context.Context, error) {
+	tx, err := x.db.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -70,22 +86,30 @@ func (x *SQL) BeginxContext(ctx context.Context) (*SQLTX, context.Context, error
 		logging.TraceSQL("TRANSACTION %p : BEGIN", tx)
 	}
 
-	return &SQLTX{
-		Tx: tx,
-		Commit: func() error {
-			if setting.Runtime.Env == "local" {
-				logging.TraceSQL("TRANSACTION %p : COMMIT", tx)
-			}
-			return tx.Commit()
-		},
+	sqltx := &SQLTX{Tx: tx}
+	sqltx.Commit = func() error {
+		if setting.Runtime.Env == "local" {
+			logging.TraceSQL("TRANSACTION %p : COMMIT", tx)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		// rollback 되면 실행하지 않음
+		for _, fn := range sqltx.afterCommit {
+			fn()
+		}
+		sqltx.afterCommit = nil
+		return nil
+	}
+	sqltx.Rollback = func() error {
+		if setting.Runtime.Env == "local" {
+			logging.TraceSQL("TRANSACTION %p : ROLLBACK", tx)
+		}
+		sqltx.afterCommit = nil
+		return tx.Rollback()
+	}
 
-		Rollback: func() error {
-			if setting.Runtime.Env == "local" {
-				logging.TraceSQL("TRANSACTION %p : ROLLBACK", tx)
-			}
-			return tx.Rollback()
-		},
-	}, ctx, nil
+	return sqltx, ctx, nil
 }
 
 // SQLTX :
@@ -93,6 +117,13 @@ type SQLTX struct {
 	Tx       *sql.Tx
 	Commit   func() error
 	Rollback func() error
+
+	afterCommit []func()
+}
+
+// AfterCommit : commit 이 성공한 뒤 등록한 순서대로 실행 (캐시 무효화 등)
+func (x *SQLTX) AfterCommit(fn func()) {
+	x.afterCommit = append(x.afterCommit, fn)
 }
 
 func (x *SQLTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
@@ -126,6 +157,17 @@ func (x *SQLTX) QueryRowContext(ctx context.Context, query string, args ...inter
 	return x.Tx.QueryRowContext(ctx, query, args...)
 }
 
+// ConfirmOptionClause : ? 1 = $n::text 자리에 삽입될 옵션 절 검사. 옵션 절은 SQL 에 그대로 삽입되므로
+// 실행 전에 이 검사를 통과한 값만 query 인자로 넘겨야 한다
+func ConfirmOptionClause(clause string) error {
+	if !optionClauseRegexp.MatchString(clause) {
+		logging.Error(ErrInvalidOptionClause, "Rejected list option clause: %q", clause)
+		return ErrInvalidOptionClause
+	}
+	return nil
+}
+
+// confirmQuery : ? 1 = $n::text 줄을 옵션 절로 바꾸고 그 인자를 제거
 func confirmQuery(query string, args ...interface{}) (string, []interface{}) {
 	// Read query line by line
 	querySplit := strings.Split(query, "\n")
@@ -134,8 +176,7 @@ func confirmQuery(query string, args ...interface{}) (string, []interface{}) {
 		switch {
 		case strings.HasPrefix(cut, "?"): // ? 1 = $4::text
 			// 정규식으로 $ 뒤에 오는 숫자 추출
-			reg := regexp.MustCompile(`\$(\d+)`)
-			matches := reg.FindStringSubmatch(line)
+			matches := optionParamRegexp.FindStringSubmatch(line)
 			if len(matches) == 0 || len(matches[0]) == 0 {
 				break
 			}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"errors"
+	"testing"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestOptionClauseRegexp(t *testing.T) {
+	tests := []struct {
+		clause string
+		valid  bool
+	}{
+		{"", true},
+		{"ORDER BY name ASC", true},
+		{"ORDER BY name ASC, created_at DESC", true},
+		{"LIMIT 10", true},
+		{"LIMIT 10 OFFSET 20", true},
+		{"ORDER BY birthday DESC LIMIT 11", true},
+		{"ORDER BY name ASC, birthday DESC LIMIT 10 OFFSET 0", true},
+
+		{"ORDER BY name", false},
+		{"ORDER BY name asc", false},
+		{"ORDER BY name ASCENDING", false},
+		{"ORDER BY Name ASC", false},
+		{"ORDER BY 1 ASC", false},
+		{"ORDER BY name ASC,birthday DESC", false},
+		{"ORDER BY name ASC; DROP TABLE appuser", false},
+		{"ORDER BY name ASC -- ", false},
+		{"ORDER BY (SELECT 1) ASC", false},
+		{"ORDER BY \"name\" ASC", false},
+		{"ORDER BY name ASC\nLIMIT 10", false},
+		{"LIMIT -1", false},
+		{"LIMIT 10 OFFSET", false},
+		{"LIMIT ALL", false},
+		{"OFFSET 10", false},
+		{"WHERE 1 = 1", false},
+	}
+
+	for _, tt := range tests {
+		if got := optionClauseRegexp.MatchString(tt.clause); got != tt.valid {
+			t.Errorf("optionClauseRegexp.MatchString(%q) = %v, want %v", tt.clause, got, tt.valid)
+		}
+	}
+}
+
+func TestConfirmOptionClause(t *testing.T) {
+	if err := ConfirmOptionClause("ORDER BY name DESC LIMIT 10 OFFSET 0"); err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+	if err := ConfirmOptionClause("ORDER BY name ASC; DELETE FROM appuser"); !errors.Is(err, ErrInvalidOptionClause) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidOptionClause)
+	}
+}
+
+func TestConfirmQuery(t *testing.T) {
+	query := "SELECT * FROM appuser\nWHERE name = $1\n? 1 = $2::text"
+
+	tests := []struct {
+		name   string
+		option null.String
+		want   string
+	}{
+		{"option", null.StringFrom("ORDER BY name DESC LIMIT 10 OFFSET 0"), "SELECT * FROM appuser\nWHERE name = $1\nORDER BY name DESC LIMIT 10 OFFSET 0"},
+		{"no option", null.String{}, "SELECT * FROM appuser\nWHERE name = $1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args := confirmQuery(query, "kim", tt.option)
+			if got != tt.want {
+				t.Errorf("query = %q, want %q", got, tt.want)
+			}
+			if len(args) != 1 || args[0] != "kim" {
+				t.Errorf("args = %v, want [kim]", args)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+/*
+	JWT 서명 검증용 공개키 관리
+	PEM 파일(kid 는 파일 이름)과 JWKS 문서(파일 또는 URL)를 함께 사용할 수 있으며,
+	rotation 중에는 여러 kid 가 동시에 유효하다
+	https://www.rfc-editor.org/rfc/rfc7517
+*/
+
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	logging "fiber-boilerplate/internal/pkg/logging"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// DefaultRefresh : JWKS URL 재조회 주기
+	DefaultRefresh = 5 * time.Minute
+	// minRefetch : 모르는 kid 로 인한 재조회 최소 간격 (임의 kid 로 IdP 를 두드리지 못하도록)
+	minRefetch = 30 * time.Second
+	// fetchTimeout :
+	fetchTimeout = 10 * time.Second
+)
+
+// Algorithms : 공개키로 검증하는 서명 알고리즘
+var Algorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// errors
+var (
+	ErrKeyNotFound  = errors.New("jwks: signing key not found")
+	ErrKeyMismatch  = errors.New("jwks: signing key does not match token algorithm")
+	ErrMissingKeyID = errors.New("jwks: token has no kid and more than one key is configured")
+	ErrNoUsableKey  = errors.New("jwks: no usable signing key")
+)
+
+// ConfigBlock :
+type ConfigBlock struct {
+	PEMFiles []string      // 공개키 또는 인증서 PEM 파일. kid 는 확장자를 뺀 파일 이름
+	JWKS     string        // JWKS 파일 경로 또는 http(s) URL
+	Refresh  time.Duration // JWKS URL 재조회 주기 (0 이면 DefaultRefresh)
+}
+
+// KeySetBlock : kid 별 공개키
+type KeySetBlock struct {
+	mu        sync.RWMutex
+	static    map[string]crypto.PublicKey // PEM 파일, JWKS 파일
+	remote    map[string]crypto.PublicKey // JWKS URL
+	fetchedAt time.Time
+
+	fetchMu    sync.Mutex
+	refreshing atomic.Bool
+	url        string
+	refresh    time.Duration
+	client     *http.Client
+}
+
+// New : 파일은 즉시 읽고 실패하면 error. URL 은 첫 조회 실패 시 로그만 남기고 검증 시점에 다시 시도한다
+func New(config ConfigBlock) (*KeySetBlock, error) {
+	k := &KeySetBlock{
+		static:  make(map[string]crypto.PublicKey),
+		remote:  make(map[string]crypto.PublicKey),
+		refresh: config.Refresh,
+		client:  &http.Client{Timeout: fetchTimeout},
+	}
+	if k.refresh <= 0 {
+		k.refresh = DefaultRefresh
+	}
+
+	for _, file := range config.PEMFiles {
+		file = strings.TrimSpace(file)
+		if file == "" {
+			continue
+		}
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: %w", err)
+		}
+		key, err := parsePEM(raw)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: %s: %w", file, err)
+		}
+		k.static[strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))] = key
+	}
+
+	switch source := strings.TrimSpace(config.JWKS); {
+	case source == "":
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		k.url = source
+		if err := k.fetch(context.Background()); err != nil {
+			logging.Warn(err, "jwks: initial fetch failed, retrying on demand: %s", source)
+		}
+	default:
+		raw, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: %w", err)
+		}
+		keys, err := parseJWKS(raw)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: %s: %w", source, err)
+		}
+		for kid, key := range keys {
+			k.static[kid] = key
+		}
+	}
+
+	return k, nil
+}
+
+// Keyfunc : jwt.Keyfunc. kid 로 키를 고르고 알고리즘과 키 종류가 맞는지 확인
+func (k *KeySetBlock) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	// 주기 재조회는 요청을 기다리게 하지 않음. 조회가 끝날 때까지 캐시된 키로 검증
+	if k.url != "" && k.stale(k.refresh) {
+		k.refetchAsync()
+	}
+
+	key, err := k.lookup(kid)
+	if errors.Is(err, ErrKeyNotFound) && k.url != "" && k.stale(minRefetch) {
+		// rotation 으로 새 kid 가 추가됐을 수 있음. 모르는 kid 일 때만 조회를 기다리며, minRefetch 에 한 번으로 제한
+		k.refetch(minRefetch)
+		key, err = k.lookup(kid)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !matches(token.Method, key) {
+		return nil, ErrKeyMismatch
+	}
+
+	return key, nil
+}
+
+// Len : 현재 유효한 키 수
+func (k *KeySetBlock) Len() int {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return len(k.static) + len(k.remote)
+}
+
+func (k *KeySetBlock) lookup(kid string) (crypto.PublicKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if kid == "" {
+		// kid 가 없는 토큰은 키가 하나일 때만 허용
+		if len(k.static)+len(k.remote) != 1 {
+			return nil, ErrMissingKeyID
+		}
+		for _, key := range k.static {
+			return key, nil
+		}
+		for _, key := range k.remote {
+			return key, nil
+		}
+	}
+
+	if key, ok := k.static[kid]; ok {
+		return key, nil
+	}
+	if key, ok := k.remote[kid]; ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("%w: kid=%q", ErrKeyNotFound, kid)
+}
+
+func (k *KeySetBlock) stale(age time.Duration) bool {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return time.Since(k.fetchedAt) >= age
+}
+
+// refetchAsync : 백그라운드 재조회. 이미 진행 중이면 무시
+func (k *KeySetBlock) refetchAsync() {
+	if !k.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer k.refreshing.Store(false)
+		k.refetch(k.refresh)
+	}()
+}
+
+// refetch : 동시에 여러 요청이 와도 한 번만 조회. 실패하면 기존 키 유지
+func (k *KeySetBlock) refetch(age time.Duration) {
+	k.fetchMu.Lock()
+	defer k.fetchMu.Unlock()
+
+	if !k.stale(age) {
+		return
+	}
+	if err := k.fetch(context.Background()); err != nil {
+		logging.Warn(err, "jwks: refresh failed, keeping %d cached keys: %s", k.Len(), k.url)
+	}
+}
+
+func (k *KeySetBlock) fetch(ctx context.Context) error {
+	// 실패해도 minRefetch 동안은 다시 조회하지 않음
+	defer func() {
+		k.mu.Lock()
+		k.fetchedAt = time.Now()
+		k.mu.Unlock()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	keys, err := parseJWKS(raw)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.remote = keys
+	k.mu.Unlock()
+	logging.Trace("jwks: fetched %d keys from %s", len(keys), k.url)
+
+	return nil
+}
+
+// matches : alg 와 키 종류가 다르면 거부 (algorithm confusion 방지)
+func matches(method jwt.SigningMethod, key crypto.PublicKey) bool {
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		_, ok := key.(*rsa.PublicKey)
+		return ok
+	case *jwt.SigningMethodECDSA:
+		_, ok := key.(*ecdsa.PublicKey)
+		return ok
+	case *jwt.SigningMethodEd25519:
+		_, ok := key.(ed25519.PublicKey)
+		return ok
+	default:
+		return false
+	}
+}
@@ -0,0 +1,140 @@
+package jwks
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksServer : 요청 수를 세고, block 이 닫힐 때까지 응답을 미룰 수 있는 JWKS URL
+type jwksServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	raw      []byte
+	block    chan struct{}
+	requests atomic.Int32
+}
+
+func newJWKSServer(t *testing.T, raw []byte) *jwksServer {
+	t.Helper()
+	s := &jwksServer{raw: raw}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		s.mu.Lock()
+		raw, block := s.raw, s.block
+		s.mu.Unlock()
+		if block != nil {
+			<-block
+		}
+		w.Write(raw)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) set(raw []byte, block chan struct{}) {
+	s.mu.Lock()
+	s.raw, s.block = raw, block
+	s.mu.Unlock()
+}
+
+func token(kid string) *jwt.Token {
+	return &jwt.Token{Method: jwt.SigningMethodES256, Header: map[string]interface{}{"kid": kid}}
+}
+
+// expire : 마지막 조회 시각을 age 만큼 과거로
+func (k *KeySetBlock) expire(age time.Duration) {
+	k.mu.Lock()
+	k.fetchedAt = time.Now().Add(-age)
+	k.mu.Unlock()
+}
+
+// TestKeyfuncServesStaleCache : 주기 재조회가 느려도 캐시된 키로 바로 검증
+func TestKeyfuncServesStaleCache(t *testing.T) {
+	server := newJWKSServer(t, marshalJWKS(t, ecJWK(t, "old")))
+	k, err := New(ConfigBlock{JWKS: server.URL, Refresh: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := make(chan struct{})
+	server.set(marshalJWKS(t, ecJWK(t, "new")), block)
+	k.expire(time.Hour)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := k.Keyfunc(token("old"))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Keyfunc(old) err = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Keyfunc blocked on a stale refresh")
+	}
+
+	close(block)
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := k.lookup("new"); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("background refresh did not replace the keys")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestKeyfuncUnknownKid : 모르는 kid 는 한 번 조회를 기다리고, minRefetch 동안은 다시 조회하지 않음
+func TestKeyfuncUnknownKid(t *testing.T) {
+	server := newJWKSServer(t, marshalJWKS(t, ecJWK(t, "a")))
+	k, err := New(ConfigBlock{JWKS: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.set(marshalJWKS(t, ecJWK(t, "a"), ecJWK(t, "b")), nil)
+	k.expire(minRefetch)
+
+	if _, err := k.Keyfunc(token("b")); err != nil {
+		t.Fatalf("Keyfunc(b) err = %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := k.Keyfunc(token("unknown")); !errors.Is(err, ErrKeyNotFound) {
+			t.Fatalf("Keyfunc(unknown) err = %v, want %v", err, ErrKeyNotFound)
+		}
+	}
+	if got := server.requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+// TestKeyfuncKeepsKeysOnBadRefresh : 쓸 수 있는 키가 없는 문서로 바뀌면 기존 키 유지
+func TestKeyfuncKeepsKeysOnBadRefresh(t *testing.T) {
+	server := newJWKSServer(t, marshalJWKS(t, ecJWK(t, "a")))
+	k, err := New(ConfigBlock{JWKS: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsupported := ecJWK(t, "b")
+	unsupported.Crv = "secp256k1"
+	server.set(marshalJWKS(t, unsupported), nil)
+	k.expire(minRefetch)
+
+	if _, err := k.Keyfunc(token("b")); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("Keyfunc(b) err = %v, want %v", err, ErrKeyNotFound)
+	}
+	if _, err := k.Keyfunc(token("a")); err != nil {
+		t.Fatalf("Keyfunc(a) err = %v", err)
+	}
+}
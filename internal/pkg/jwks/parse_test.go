@@ -0,0 +1,129 @@
+package jwks
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sort"
+	"testing"
+)
+
+func b64(raw []byte) string {
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func rsaJWK(t *testing.T, kid string) jwkBlock {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jwkBlock{Kty: "RSA", Kid: kid, N: b64(key.N.Bytes()), E: b64(big.NewInt(int64(key.E)).Bytes())}
+}
+
+func ecJWK(t *testing.T, kid string) jwkBlock {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jwkBlock{Kty: "EC", Kid: kid, Crv: "P-256", X: b64(key.X.Bytes()), Y: b64(key.Y.Bytes())}
+}
+
+func okpJWK(t *testing.T, kid string) jwkBlock {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jwkBlock{Kty: "OKP", Kid: kid, Crv: "Ed25519", X: b64(pub)}
+}
+
+func marshalJWKS(t *testing.T, keys ...jwkBlock) []byte {
+	t.Helper()
+	raw, err := json.Marshal(jwksBlock{Keys: keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, ecKey, okpKey := rsaJWK(t, "rsa"), ecJWK(t, "ec"), okpJWK(t, "okp")
+
+	secp256k1 := ecJWK(t, "k1")
+	secp256k1.Crv = "secp256k1"
+	x448 := okpJWK(t, "x448")
+	x448.Crv = "X448"
+	offCurve := ecJWK(t, "off")
+	offCurve.Y = b64([]byte{1})
+	shortOKP := okpJWK(t, "short")
+	shortOKP.X = b64([]byte{1, 2, 3})
+	badExponent := rsaJWK(t, "exp")
+	badExponent.E = b64([]byte{1})
+	badBase64 := rsaJWK(t, "b64")
+	badBase64.N = "!!!"
+	encryption := rsaJWK(t, "enc")
+	encryption.Use = "enc"
+	signing := ecJWK(t, "sig")
+	signing.Use = "sig"
+	symmetric := jwkBlock{Kty: "oct", Kid: "oct"}
+
+	tests := []struct {
+		name    string
+		raw     []byte
+		want    []string
+		wantErr error
+	}{
+		{"all types", marshalJWKS(t, rsaKey, ecKey, okpKey), []string{"ec", "okp", "rsa"}, nil},
+		{"use sig", marshalJWKS(t, signing), []string{"sig"}, nil},
+		{"skip enc and oct", marshalJWKS(t, rsaKey, encryption, symmetric), []string{"rsa"}, nil},
+		{"skip unsupported curve", marshalJWKS(t, secp256k1, ecKey), []string{"ec"}, nil},
+		{"skip non-Ed25519 OKP", marshalJWKS(t, x448, okpKey), []string{"okp"}, nil},
+		{"skip invalid keys", marshalJWKS(t, offCurve, shortOKP, badExponent, badBase64, rsaKey), []string{"rsa"}, nil},
+
+		{"only unsupported", marshalJWKS(t, secp256k1, x448), nil, ErrNoUsableKey},
+		{"only enc", marshalJWKS(t, encryption), nil, ErrNoUsableKey},
+		{"empty", []byte(`{"keys":[]}`), nil, ErrNoUsableKey},
+		{"malformed", []byte(`{"keys":`), nil, nil},
+		{"not an object", []byte(`[]`), nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := parseJWKS(tt.raw)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatalf("keys = %v, want error", keys)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+
+			got := make([]string, 0, len(keys))
+			for kid := range keys {
+				got = append(got, kid)
+			}
+			sort.Strings(got)
+			if len(got) != len(tt.want) {
+				t.Fatalf("kids = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("kids = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+
+	"fiber-boilerplate/internal/defs"
+	"fiber-boilerplate/internal/pkg/authz"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 기본 메시지 type
+const (
+	// MessageSubscribe : data = {"topics": [...]}. 연결의 구독에 topic 추가, reply 는 구독 중인 topic 목록
+	MessageSubscribe = "subscribe"
+	// MessageUnsubscribe : data = {"topics": [...]}. 연결의 구독에서 topic 제거, reply 는 구독 중인 topic 목록
+	MessageUnsubscribe = "unsubscribe"
+)
+
+// ErrUnknownMessage : handler 가 등록되지 않은 메시지
+var ErrUnknownMessage = defs.NewAppError(http.StatusBadRequest, "unknown_message", "message type has no handler")
+
+// ClientBlock : WebSocket 연결 하나. 연결할 때 인증한 토큰의 정보와 연결의 구독
+type ClientBlock struct {
+	AppuserUUID string
+	Claims      jwt.MapClaims
+	Permission  *authz.PermissionBlock
+	Subscriber  *SubscriberBlock
+}
+
+// topicsMessageBlock : subscribe / unsubscribe 의 data 와 reply
+type topicsMessageBlock struct {
+	Topics []string `json:"topics"`
+}
+
+// MessageHandlerFunc : 클라이언트가 보낸 메시지 처리. 반환값은 reply 의 data, error 는 error 메시지로 보냄.
+// ctx 는 연결이 끊기면 취소됨
+type MessageHandlerFunc func(ctx context.Context, client *ClientBlock, data json.RawMessage) (interface{}, error)
+
+var (
+	messageHandlers   = map[string]MessageHandlerFunc{}
+	messageHandlersMu sync.RWMutex
+)
+
+// HandleMessage : type 이 name 인 메시지의 handler 등록. 같은 name 이면 교체
+func HandleMessage(name string, fn MessageHandlerFunc) {
+	messageHandlersMu.Lock()
+	defer messageHandlersMu.Unlock()
+
+	messageHandlers[name] = fn
+}
+
+// DispatchMessage : 등록된 handler 로 처리. 없으면 ErrUnknownMessage
+func DispatchMessage(ctx context.Context, client *ClientBlock, name string, data json.RawMessage) (interface{}, error) {
+	messageHandlersMu.RLock()
+	fn, ok := messageHandlers[name]
+	messageHandlersMu.RUnlock()
+
+	if !ok {
+		return nil, ErrUnknownMessage.WithMessage("unknown message type: %s", name)
+	}
+
+	return fn(ctx, client, data)
+}
+
+// handleSubscribe : 연결할 때와 같은 topic 권한 검사 (AuthorizeTopics)
+func handleSubscribe(ctx context.Context, client *ClientBlock, data json.RawMessage) (interface{}, error) {
+	topics, err := parseTopics(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := AuthorizeTopics(client.Permission, client.AppuserUUID, topics...); err != nil {
+		return nil, err
+	}
+	if err := client.Subscriber.Add(topics...); err != nil {
+		return nil, topicsError(err)
+	}
+
+	return topicsMessageBlock{Topics: client.Subscriber.Topics()}, nil
+}
+
+// handleUnsubscribe :
+func handleUnsubscribe(ctx context.Context, client *ClientBlock, data json.RawMessage) (interface{}, error) {
+	topics, err := parseTopics(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Subscriber.Remove(topics...); err != nil {
+		return nil, topicsError(err)
+	}
+
+	return topicsMessageBlock{Topics: client.Subscriber.Topics()}, nil
+}
+
+func parseTopics(data json.RawMessage) ([]string, error) {
+	var msg topicsMessageBlock
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, defs.ErrMalformedBody.Wrap(err)
+	}
+	if len(msg.Topics) == 0 {
+		return nil, defs.ErrInvalidParameter.WithMessage("topics is required")
+	}
+	return msg.Topics, nil
+}
+
+func topicsError(err error) error {
+	if errors.Is(err, ErrInvalidTopic) {
+		return defs.ErrInvalidParameter.WithMessage("invalid topic (up to %d)", MaxTopics).Wrap(err)
+	}
+	return err
+}
@@ -0,0 +1,475 @@
+/*
+	실시간 이벤트 hub
+	topic (user:{uuid}, appuser.updated 등) 을 구독한 SSE 연결에 이벤트를 보낸다.
+	Publish 는 Redis 채널 하나로 모든 instance 에 보내고, 각 instance 는 받은 이벤트를 topic 의 구독자에게 나눠 준다.
+	이벤트는 topic 마다 길이와 ttl 이 제한된 로그(Redis Streams)에도 남겨 다시 연결한 클라이언트가 놓친 이벤트를 받을 수 있다
+*/
+
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"fiber-boilerplate/internal/defs"
+	"fiber-boilerplate/internal/models"
+	"fiber-boilerplate/internal/pkg/authz"
+	"fiber-boilerplate/internal/pkg/database"
+	logging "fiber-boilerplate/internal/pkg/logging"
+	"fiber-boilerplate/internal/pkg/util"
+)
+
+const (
+	// channel : 모든 topic 의 이벤트를 보내는 Redis 채널
+	channel = "fiber-boilerplate/realtime"
+	// redisDB : pub/sub 은 DB 와 관계없음
+	redisDB = 0
+
+	// MaxTopics : 구독 하나의 최대 topic 수
+	MaxTopics = 16
+
+	// eventLogLength : topic 마다 남겨 두는 이벤트 수 (Redis 는 대략 이 길이로 자름)
+	eventLogLength = 500
+	// eventLogTTL : 마지막 이벤트 후 로그를 유지하는 시간
+	eventLogTTL = 10 * time.Minute
+)
+
+// 이벤트 이름
+const (
+	// EventClose : 받은 구독자는 연결을 닫음. 로그에 남기지 않고 ID 가 없음
+	EventClose = "close"
+	// EventAppuserUpdated : data = {"uuid": "..."}. user:{uuid} 와 appuser.updated topic 에 발행
+	EventAppuserUpdated = "appuser.updated"
+)
+
+// TopicAppuserUpdated : 모든 사용자의 변경 알림
+const TopicAppuserUpdated = "appuser.updated"
+
+// ScopeAdmin : 모든 topic 을 구독할 수 있는 scope (admin role 과 같음)
+const ScopeAdmin = "realtime:admin"
+
+// topicRegexp : name 또는 name:key
+var topicRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}(:[A-Za-z0-9._-]{1,64})?$`)
+
+// eventRegexp : SSE frame 에 그대로 쓰므로 줄바꿈 등은 허용하지 않음
+var eventRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]{0,63}$`)
+
+// ErrInvalidTopic :
+var ErrInvalidTopic = defs.NewError("invalid topic")
+
+// ErrInvalidEvent : 이벤트 이름 형식 오류
+var ErrInvalidEvent = defs.NewError("invalid event")
+
+// ErrInvalidEventID : Last-Event-ID 형식 오류
+var ErrInvalidEventID = defs.NewError("invalid event id")
+
+// ErrSubscriptionClosed : 닫힌 구독의 topic 을 바꾸려 함
+var ErrSubscriptionClosed = defs.NewError("subscription closed")
+
+// EventBlock : SSE 의 id, event, data. ID 는 topic 로그의 ID ("<unix milli>-<seq>") 로 topic 안에서 증가
+type EventBlock struct {
+	ID    string          `json:"id,omitempty"`
+	Topic string          `json:"topic"`
+	Name  string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// SubscriberBlock : Subscribe 로 얻은 구독. 다 쓰면 Close
+type SubscriberBlock struct {
+	// topics, closed : hub.mu 로 보호
+	topics []string
+	closed bool
+	events chan EventBlock
+	done   chan struct{}
+	once   sync.Once
+
+	// replayed : Resume 에서 보낸 topic 별 마지막 ID
+	replayed map[string]string
+}
+
+// Events :
+func (s *SubscriberBlock) Events() <-chan EventBlock {
+	return s.events
+}
+
+// Done : Close 하거나 서버가 종료되면 닫힘
+func (s *SubscriberBlock) Done() <-chan struct{} {
+	return s.done
+}
+
+// Topics :
+func (s *SubscriberBlock) Topics() []string {
+	hub.mu.RLock()
+	defer hub.mu.RUnlock()
+
+	return append([]string{}, s.topics...)
+}
+
+// Add : 구독 중인 topic 에 추가. 이미 구독 중인 topic 은 무시하고, 합쳐서 MaxTopics 를 넘거나 형식이 맞지 않으면 ErrInvalidTopic
+func (s *SubscriberBlock) Add(topics ...string) error {
+	for _, topic := range topics {
+		if !ValidTopic(topic) {
+			return ErrInvalidTopic
+		}
+	}
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if s.closed {
+		return ErrSubscriptionClosed
+	}
+	added := []string{}
+	for _, topic := range topics {
+		if !slices.Contains(s.topics, topic) && !slices.Contains(added, topic) {
+			added = append(added, topic)
+		}
+	}
+	if len(s.topics)+len(added) > MaxTopics {
+		return ErrInvalidTopic
+	}
+	for _, topic := range added {
+		if hub.subscribers[topic] == nil {
+			hub.subscribers[topic] = map[*SubscriberBlock]struct{}{}
+		}
+		hub.subscribers[topic][s] = struct{}{}
+	}
+	s.topics = append(s.topics, added...)
+
+	return nil
+}
+
+// Remove : 구독 중이 아닌 topic 은 무시. 모든 topic 을 빼도 구독은 닫히지 않음
+func (s *SubscriberBlock) Remove(topics ...string) error {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if s.closed {
+		return ErrSubscriptionClosed
+	}
+	kept := s.topics[:0]
+	for _, topic := range s.topics {
+		if slices.Contains(topics, topic) {
+			hub.unsubscribe(s, topic)
+			continue
+		}
+		kept = append(kept, topic)
+	}
+	s.topics = kept
+
+	return nil
+}
+
+// Replayed : Resume 으로 이미 받은 이벤트. 로그를 읽는 동안 구독으로도 들어온 이벤트를 거를 때 사용
+func (s *SubscriberBlock) Replayed(event EventBlock) bool {
+	last, ok := s.replayed[event.Topic]
+	return ok && event.ID != "" && database.CompareStreamID(event.ID, last) <= 0
+}
+
+// Close :
+func (s *SubscriberBlock) Close() {
+	s.once.Do(func() {
+		if hub != nil {
+			hub.remove(s)
+		}
+		close(s.done)
+	})
+}
+
+type hubBlock struct {
+	redis *database.Redis
+
+	mu          sync.RWMutex
+	subscribers map[string]map[*SubscriberBlock]struct{}
+}
+
+var hub *hubBlock
+
+// Setup : ctx 가 취소되면 모든 구독을 닫음 (graceful shutdown 전에 stream 종료)
+func Setup(ctx context.Context) {
+	hub = &hubBlock{
+		redis:       database.NewRedis(redisDB, 60),
+		subscribers: map[string]map[*SubscriberBlock]struct{}{},
+	}
+
+	// 사용자 정보가 바뀌면 열려 있는 브라우저 탭에 알림
+	models.OnAppuserChanged(func(ctx context.Context, appuserUUID string) {
+		data := map[string]string{"uuid": appuserUUID}
+		for _, topic := range []string{UserTopic(appuserUUID), TopicAppuserUpdated} {
+			if err := Publish(ctx, topic, EventAppuserUpdated, data); err != nil {
+				logging.Warn(err, "realtime publish failed: %s", topic)
+			}
+		}
+	})
+
+	// WebSocket 연결의 구독 변경
+	HandleMessage(MessageSubscribe, handleSubscribe)
+	HandleMessage(MessageUnsubscribe, handleUnsubscribe)
+
+	go hub.listen(ctx)
+	logging.Info("Realtime hub ready")
+}
+
+// UserTopic : 사용자 한 명의 topic
+func UserTopic(appuserUUID string) string {
+	return util.String.Concat("user:", appuserUUID)
+}
+
+// ValidTopic :
+func ValidTopic(topic string) bool {
+	return topicRegexp.MatchString(topic)
+}
+
+// ValidEvent :
+func ValidEvent(name string) bool {
+	return eventRegexp.MatchString(name)
+}
+
+// AuthorizeTopics : admin role 또는 ScopeAdmin 이 있으면 모든 topic, 아니면 자신의 UserTopic 만.
+// 구독할 수 없는 topic 이 있으면 defs.ErrInsufficientPermission
+func AuthorizeTopics(permission *authz.PermissionBlock, appuserUUID string, topics ...string) error {
+	if permission.HasRole(authz.RoleAdmin) || permission.HasScope(ScopeAdmin) {
+		return nil
+	}
+	for _, topic := range topics {
+		if appuserUUID == "" || topic != UserTopic(appuserUUID) {
+			return defs.ErrInsufficientPermission.WithMessage("cannot subscribe to topic: %s", topic)
+		}
+	}
+	return nil
+}
+
+// Publish : 모든 instance 의 topic 구독자에게 보냄. data 는 JSON 으로 보냄
+func Publish(ctx context.Context, topic string, name string, data interface{}) error {
+	if hub == nil {
+		return defs.ErrInvalid
+	}
+	if !ValidTopic(topic) {
+		return ErrInvalidTopic
+	}
+	if !ValidEvent(name) {
+		return ErrInvalidEvent
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	event := EventBlock{
+		Topic: topic,
+		Name:  name,
+		Data:  raw,
+	}
+	if name != EventClose {
+		// 로그의 ID 를 이벤트 ID 로 사용
+		logged, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if event.ID, err = hub.redis.StreamAdd(ctx, logKey(topic), string(logged), eventLogLength, eventLogTTL); err != nil {
+			return err
+		}
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	// Redis fallback 중에는 in-process broker 로 이 instance 의 구독자에게만
+	return hub.redis.PublishChannel(ctx, channel, string(payload))
+}
+
+// Subscribe : topics 중 하나라도 형식이 맞지 않으면 ErrInvalidTopic
+func Subscribe(topics ...string) (*SubscriberBlock, error) {
+	if hub == nil {
+		return nil, defs.ErrInvalid
+	}
+	if len(topics) == 0 || len(topics) > MaxTopics {
+		return nil, ErrInvalidTopic
+	}
+	for _, topic := range topics {
+		if !ValidTopic(topic) {
+			return nil, ErrInvalidTopic
+		}
+	}
+
+	// buffer 크기와 가득 찼을 때의 처리는 in-process pub/sub 과 같음
+	s := &SubscriberBlock{
+		topics: append([]string{}, topics...),
+		events: make(chan EventBlock, database.PubSubConfig().Buffer),
+		done:   make(chan struct{}),
+	}
+
+	hub.mu.Lock()
+	for _, topic := range s.topics {
+		if hub.subscribers[topic] == nil {
+			hub.subscribers[topic] = map[*SubscriberBlock]struct{}{}
+		}
+		hub.subscribers[topic][s] = struct{}{}
+	}
+	hub.mu.Unlock()
+
+	return s, nil
+}
+
+// Resume : Subscribe 후 각 topic 로그에서 lastEventID 뒤의 이벤트를 ID 순으로 반환.
+// 반환한 이벤트가 구독으로 다시 들어오면 Replayed 로 거름. 로그를 읽지 못하면 놓친 이벤트 없이 구독만 함
+func Resume(ctx context.Context, lastEventID string, topics ...string) (*SubscriberBlock, []EventBlock, error) {
+	if _, _, err := database.ParseStreamID(lastEventID); err != nil {
+		return nil, nil, ErrInvalidEventID
+	}
+
+	// 로그를 읽는 동안 발행된 이벤트를 놓치지 않도록 먼저 구독
+	s, err := Subscribe(topics...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	missed := []EventBlock{}
+	replayed := map[string]string{}
+	for _, topic := range s.topics {
+		entries, err := hub.redis.StreamRange(ctx, logKey(topic), lastEventID, eventLogLength)
+		if err != nil {
+			logging.Warn(err, "realtime replay failed: %s", topic)
+			continue
+		}
+		for _, entry := range entries {
+			var event EventBlock
+			if err := json.Unmarshal([]byte(entry.Value), &event); err != nil {
+				logging.Warn(err, "invalid realtime log entry: %s %s", topic, entry.ID)
+				continue
+			}
+			event.ID = entry.ID
+			missed = append(missed, event)
+			replayed[topic] = entry.ID
+		}
+	}
+	s.replayed = replayed
+	sort.SliceStable(missed, func(i, j int) bool {
+		return database.CompareStreamID(missed[i].ID, missed[j].ID) < 0
+	})
+
+	return s, missed, nil
+}
+
+// logKey : topic 의 이벤트 로그
+func logKey(topic string) string {
+	return util.String.Concat("realtime/log/", topic)
+}
+
+func (h *hubBlock) remove(s *SubscriberBlock) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, topic := range s.topics {
+		h.unsubscribe(s, topic)
+	}
+	s.closed = true
+}
+
+// unsubscribe : h.mu 를 잡은 상태에서 호출
+func (h *hubBlock) unsubscribe(s *SubscriberBlock, topic string) {
+	delete(h.subscribers[topic], s)
+	if len(h.subscribers[topic]) == 0 {
+		delete(h.subscribers, topic)
+	}
+}
+
+// dispatch : 구독자의 buffer 가 가득 차 있으면 그 구독자에게는 버리거나 구독을 닫음 (느린 구독자가 다른 구독자를 막지 않도록).
+// 닫힌 SSE 는 클라이언트가 다시 연결하면서 Last-Event-ID 로 놓친 이벤트를 받음
+func (h *hubBlock) dispatch(event EventBlock) {
+	disconnect := database.PubSubConfig().SlowConsumer == database.SlowConsumerDisconnect
+	var slow []*SubscriberBlock
+
+	h.mu.RLock()
+	for s := range h.subscribers[event.Topic] {
+		select {
+		case s.events <- event:
+		default:
+			if disconnect {
+				slow = append(slow, s)
+			} else {
+				logging.Warn(nil, "realtime subscriber is too slow, event dropped: %s %s", event.Topic, event.ID)
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, s := range slow {
+		logging.Warn(nil, "realtime subscriber is too slow, disconnected: %v", s.Topics())
+		s.Close()
+	}
+}
+
+// listen : 채널의 이벤트를 구독자에게. 구독이 끝나면 (Redis 와 fallback 사이 전환 등) 바로 다시 구독
+func (h *hubBlock) listen(ctx context.Context) {
+	defer h.closeAll()
+
+	for {
+		var wait time.Duration
+		if sub, err := h.redis.SubscribeChannel(ctx, channel); err == nil {
+			h.receive(ctx, sub)
+		} else {
+			logging.Warn(err, "realtime subscribe failed")
+			wait = time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			logging.Trace("realtime listener stopped")
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// receive : 구독이 끝나거나 ctx 가 취소될 때까지 처리
+func (h *hubBlock) receive(ctx context.Context, sub *database.SubscriptionBlock) {
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var event EventBlock
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				logging.Warn(err, "invalid realtime event")
+				continue
+			}
+			h.dispatch(event)
+		}
+	}
+}
+
+// closeAll : 서버 종료
+func (h *hubBlock) closeAll() {
+	h.mu.RLock()
+	var all []*SubscriberBlock
+	seen := map[*SubscriberBlock]struct{}{}
+	for _, subscribers := range h.subscribers {
+		for s := range subscribers {
+			if _, ok := seen[s]; !ok {
+				seen[s] = struct{}{}
+				all = append(all, s)
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, s := range all {
+		s.Close()
+	}
+}
@@ -0,0 +1,80 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"fiber-boilerplate/internal/defs"
+	"fiber-boilerplate/internal/pkg/auth"
+	"fiber-boilerplate/internal/pkg/util"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// IssueTicket : EventSource 는 헤더를 보낼 수 없으므로 query 로 보낼 ticket 을 발급.
+// 토큰의 클레임을 저장하고 ttl 과 토큰의 만료 중 먼저 오는 시각까지 유효. 유효한 동안은 자동 재연결에 다시 쓸 수 있음
+func IssueTicket(ctx context.Context, claims jwt.MapClaims, ttl time.Duration) (string, time.Time, error) {
+	if hub == nil {
+		return "", time.Time{}, defs.ErrInvalid
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil && exp.Before(expiresAt) {
+		expiresAt = exp.Time
+	}
+	if !expiresAt.After(now) {
+		return "", time.Time{}, defs.ErrInvalidToken
+	}
+
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	// refresh token 과 같은 형식. 저장소에는 hash 만
+	ticket, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if err := hub.redis.SetTTL(ctx, ticketKey(hash), raw, expiresAt.Sub(now)); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return ticket, expiresAt, nil
+}
+
+// RedeemTicket : ticket 을 발급한 토큰의 클레임. 없거나 만료되면 defs.ErrInvalidTicket. 폐기 여부는 호출하는 쪽에서 확인
+func RedeemTicket(ctx context.Context, ticket string) (jwt.MapClaims, error) {
+	if hub == nil {
+		return nil, defs.ErrInvalid
+	}
+	if ticket == "" {
+		return nil, defs.ErrInvalidTicket
+	}
+
+	value, found, err := hub.redis.Get(ctx, ticketKey(auth.HashRefreshToken(ticket)))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, defs.ErrInvalidTicket
+	}
+
+	raw, _ := value.([]byte)
+	claims := jwt.MapClaims{}
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return nil, defs.ErrInvalidTicket.Wrap(err)
+	}
+	// 저장소 TTL 과 별개로 한 번 더 확인
+	if exp, err := claims.GetExpirationTime(); err != nil || exp == nil || !time.Now().Before(exp.Time) {
+		return nil, defs.ErrInvalidTicket
+	}
+
+	return claims, nil
+}
+
+// ticketKey :
+func ticketKey(hash string) string {
+	return util.String.Concat("realtime/ticket/", hash)
+}
@@ -0,0 +1,193 @@
+/*
+	JWT 폐기 목록
+	jti 단위 폐기, 세션(sid) 단위 폐기와 사용자 단위 폐기(not before 이전에 발급된 토큰 모두 거부)를 Redis 에 저장한다.
+	시작할 때 Redis 에 연결하지 못하면 연결될 때까지 in-memory cache 를 사용하므로 해당 인스턴스에서만 유효하다.
+	한 번 연결된 뒤의 장애 중에는 in-memory 로 전환하지 않고 error 를 반환한다 (요청은 503)
+*/
+
+package revocation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"fiber-boilerplate/internal/defs"
+	"fiber-boilerplate/internal/pkg/database"
+	logging "fiber-boilerplate/internal/pkg/logging"
+	"fiber-boilerplate/internal/pkg/util"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// redisDB : session(0) 의 Flush 에 함께 지워지지 않도록 별도 DB 사용
+	redisDB = 1
+	// expiryLeeway : 서버 간 시계 차이를 고려해 exp 이후에도 조금 더 보관
+	expiryLeeway = time.Minute
+)
+
+// ErrRevoked :
+var ErrRevoked = errors.New("revocation: token revoked")
+
+// StoreBlock :
+type StoreBlock struct {
+	redis *database.Redis
+	// maxLifetime : 가장 긴 토큰 유효 기간. 사용자 단위 폐기와 exp 를 모르는 jti 폐기의 보관 기간
+	maxLifetime time.Duration
+}
+
+var store *StoreBlock
+
+// Setup : maxLifetime 은 발급되는 토큰의 최대 유효 기간 이상이어야 함
+func Setup(maxLifetime time.Duration) {
+	store = &StoreBlock{
+		redis:       database.NewStrictRedis(redisDB, int(maxLifetime/time.Second)),
+		maxLifetime: maxLifetime,
+	}
+	logging.Info("Token revocation store ready, retention %s", maxLifetime)
+}
+
+// RevokeToken : jti 하나를 exp 까지 거부. exp 를 모르면 zero value
+func RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	if store == nil || jti == "" {
+		return defs.ErrInvalid
+	}
+
+	ttl := store.maxLifetime
+	if !expiresAt.IsZero() {
+		ttl = time.Until(expiresAt) + expiryLeeway
+		if ttl <= 0 {
+			// 이미 만료돼 검증을 통과할 수 없는 토큰
+			return nil
+		}
+	}
+
+	if err := store.redis.SetTTL(ctx, tokenKey(jti), []byte("1"), ttl); err != nil {
+		return err
+	}
+	logging.Trace("token revoked: jti=%s ttl=%s", jti, ttl)
+
+	return nil
+}
+
+// RevokeSession : sid 클레임이 같은 토큰을 모두 거부 (기기 하나 로그아웃).
+// 새 토큰은 refresh token family 폐기로 막으므로 가장 긴 토큰 유효 기간 동안만 보관
+func RevokeSession(ctx context.Context, sid string) error {
+	if store == nil || sid == "" {
+		return defs.ErrInvalid
+	}
+
+	if err := store.redis.SetTTL(ctx, sessionKey(sid), []byte("1"), store.maxLifetime); err != nil {
+		return err
+	}
+	logging.Trace("session revoked: sid=%s", sid)
+
+	return nil
+}
+
+// RevokeAppuser : notBefore 이전(같은 millisecond 포함)에 발급된 사용자의 토큰을 모두 거부
+func RevokeAppuser(ctx context.Context, uuid string, notBefore time.Time) error {
+	if store == nil || uuid == "" {
+		return defs.ErrInvalid
+	}
+
+	value := []byte(strconv.FormatInt(notBefore.UnixMilli(), 10))
+	if err := store.redis.SetTTL(ctx, appuserKey(uuid), value, store.maxLifetime); err != nil {
+		return err
+	}
+	logging.Trace("appuser tokens revoked: uuid=%s not before=%s", uuid, notBefore.Format(time.RFC3339Nano))
+
+	return nil
+}
+
+// Check : 폐기된 토큰이면 ErrRevoked. issuedAt 이 zero 면 사용자 단위 폐기가 있을 때 거부.
+// jti, sid, 사용자 단위 폐기를 한 번의 MGET 으로 조회
+func Check(ctx context.Context, jti string, sid string, uuid string, issuedAt time.Time) error {
+	if store == nil {
+		return nil
+	}
+
+	var keys, labels []string
+	if jti != "" {
+		keys = append(keys, tokenKey(jti))
+		labels = append(labels, "jti="+jti)
+	}
+	if sid != "" {
+		keys = append(keys, sessionKey(sid))
+		labels = append(labels, "sid="+sid)
+	}
+	if uuid != "" {
+		keys = append(keys, appuserKey(uuid))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	values, err := store.redis.MGet(ctx, keys...)
+	if err != nil {
+		return err
+	}
+	for i, label := range labels {
+		if values[i] != nil {
+			return fmt.Errorf("%w: %s", ErrRevoked, label)
+		}
+	}
+
+	if uuid != "" && values[len(keys)-1] != nil {
+		raw, _ := values[len(keys)-1].([]byte)
+		notBefore, err := strconv.ParseInt(string(raw), 10, 64)
+		if err != nil {
+			return fmt.Errorf("revocation: invalid not before for %s: %w", uuid, err)
+		}
+		if issuedAt.IsZero() || issuedAt.UnixMilli() <= notBefore {
+			return fmt.Errorf("%w: uuid=%s issued before %d", ErrRevoked, uuid, notBefore)
+		}
+	}
+
+	return nil
+}
+
+// CheckClaims : 토큰 클레임의 jti, sid, uuid, iat 로 Check
+func CheckClaims(ctx context.Context, claims jwt.MapClaims) error {
+	jti, _ := claims["jti"].(string)
+	sid, _ := claims["sid"].(string)
+	uuid, _ := claims["uuid"].(string)
+
+	return Check(ctx, jti, sid, uuid, issuedAt(claims))
+}
+
+// issuedAt : iat 를 millisecond 까지. jwt.NumericDate 는 jwt.TimePrecision (초) 로 잘리므로 직접 변환
+func issuedAt(claims jwt.MapClaims) time.Time {
+	var seconds float64
+	switch iat := claims["iat"].(type) {
+	case float64:
+		seconds = iat
+	case json.Number:
+		f, err := iat.Float64()
+		if err != nil {
+			return time.Time{}
+		}
+		seconds = f
+	default:
+		return time.Time{}
+	}
+
+	return time.UnixMilli(int64(math.Round(seconds * 1000)))
+}
+
+func tokenKey(jti string) string {
+	return util.String.Concat("revoked/jti/", jti)
+}
+
+func sessionKey(sid string) string {
+	return util.String.Concat("revoked/sid/", sid)
+}
+
+func appuserKey(uuid string) string {
+	return util.String.Concat("revoked/appuser/", uuid)
+}
@@ -0,0 +1,92 @@
+package revocation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"fiber-boilerplate/internal/pkg/database"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func setupStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	database.Setup(json.RawMessage(`{"redis": {"conn": "` + mr.Addr() + `"}}`))
+	Setup(time.Hour)
+	t.Cleanup(func() {
+		_ = store.redis.Close()
+		store = nil
+	})
+}
+
+func TestCheck(t *testing.T) {
+	setupStore(t)
+	ctx := context.Background()
+
+	notBefore := time.UnixMilli(1760680000500)
+	if err := RevokeToken(ctx, "revoked-jti", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RevokeSession(ctx, "revoked-sid"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RevokeAppuser(ctx, "u1", notBefore); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		jti      string
+		sid      string
+		uuid     string
+		issuedAt time.Time
+		revoked  bool
+	}{
+		{"none", "", "", "", time.Time{}, false},
+		{"valid", "jti", "sid", "u2", notBefore, false},
+		{"jti", "revoked-jti", "sid", "u2", notBefore, true},
+		{"sid", "jti", "revoked-sid", "u2", notBefore, true},
+		{"issued before", "jti", "sid", "u1", notBefore.Add(-time.Second), true},
+		{"issued at", "jti", "sid", "u1", notBefore, true},
+		{"issued later in the same second", "jti", "sid", "u1", notBefore.Add(time.Millisecond), false},
+		{"no iat", "jti", "sid", "u1", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(ctx, tt.jti, tt.sid, tt.uuid, tt.issuedAt)
+			if errors.Is(err, ErrRevoked) != tt.revoked {
+				t.Errorf("err = %v, revoked %v", err, tt.revoked)
+			}
+			if err != nil && !errors.Is(err, ErrRevoked) {
+				t.Errorf("unexpected err = %v", err)
+			}
+		})
+	}
+}
+
+func TestIssuedAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   time.Time
+	}{
+		{"seconds", jwt.MapClaims{"iat": float64(1760680000)}, time.UnixMilli(1760680000000)},
+		{"milliseconds", jwt.MapClaims{"iat": 1760680000.123}, time.UnixMilli(1760680000123)},
+		{"json number", jwt.MapClaims{"iat": json.Number("1760680000.5")}, time.UnixMilli(1760680000500)},
+		{"missing", jwt.MapClaims{}, time.Time{}},
+		{"string", jwt.MapClaims{"iat": "1760680000"}, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issuedAt(tt.claims); !got.Equal(tt.want) {
+				t.Errorf("issuedAt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package session
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// SchemaVersion : 저장하는 DataBlock 의 구조 버전. DataBlock, InfoBlock, models.AppuserBlock 의
+// 필드를 바꿔 이전 세션을 그대로 읽을 수 없으면 올리고, 필요하면 RegisterMigration 으로 변환 함수 등록
+const SchemaVersion = 1
+
+// 세션 codec 이름
+const (
+	CodecGob     = "gob"
+	CodecJSON    = "json"
+	CodecMsgpack = "msgpack"
+)
+
+// headerPrefix : 저장 값 = "fbs:<codec>:<version>\n" + payload.
+// 다른 언어(관리 도구)나 redis-cli 에서도 첫 줄만 읽으면 codec 과 버전을 알 수 있음
+const headerPrefix = "fbs:"
+
+var errMalformedHeader = errors.New("malformed session header")
+
+// Codec : 세션 DataBlock 직렬화
+type Codec interface {
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// MigrationFunc : 이전 버전 payload 를 현재 DataBlock 으로 변환.
+// codec 은 payload 를 저장한 codec (헤더가 없던 버전 0 은 gob)
+type MigrationFunc func(codec Codec, payload []byte) (*DataBlock, error)
+
+var (
+	codecs = map[string]Codec{
+		CodecGob:     gobCodec{},
+		CodecJSON:    jsonCodec{},
+		CodecMsgpack: msgpackCodec{},
+	}
+	migrations = map[int]MigrationFunc{
+		// 버전 0 : 헤더 없이 gob 으로 저장하던 세션. 구조가 같으므로 그대로 decode
+		0: func(codec Codec, payload []byte) (*DataBlock, error) {
+			var data DataBlock
+			if err := codec.Unmarshal(payload, &data); err != nil {
+				return nil, err
+			}
+			return &data, nil
+		},
+	}
+	codecsMu sync.RWMutex
+)
+
+// RegisterCodec : 이름이 같은 codec 은 교체
+func RegisterCodec(codec Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[codec.Name()] = codec
+}
+
+// CodecByName : 등록되지 않은 이름이면 false
+func CodecByName(name string) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecs[name]
+	return codec, ok
+}
+
+// RegisterMigration : version 으로 저장된 세션을 읽을 때 사용할 변환 함수.
+// 등록하지 않은 이전 버전의 세션은 버림 (사용자는 다시 검증되어 새 세션이 만들어짐)
+func RegisterMigration(version int, migrate MigrationFunc) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	migrations[version] = migrate
+}
+
+// encode : 헤더 + codec payload
+func encode(codec Codec, data *DataBlock) ([]byte, error) {
+	payload, err := codec.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	header := fmt.Sprintf("%s%s:%d\n", headerPrefix, codec.Name(), SchemaVersion)
+	value := make([]byte, 0, len(header)+len(payload))
+	value = append(value, header...)
+	return append(value, payload...), nil
+}
+
+// decode : 헤더의 codec 으로 decode 하므로 설정한 codec 을 바꿔도 저장돼 있던 세션을 읽을 수 있음
+func decode(value []byte) (*DataBlock, error) {
+	name, version, payload, err := parseHeader(value)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, ok := CodecByName(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown session codec: %s", name)
+	}
+
+	if version != SchemaVersion {
+		codecsMu.RLock()
+		migrate, ok := migrations[version]
+		codecsMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("unsupported session schema version: %d", version)
+		}
+		return migrate(codec, payload)
+	}
+
+	var data DataBlock
+	if err := codec.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// parseHeader : 헤더가 없으면 버전 0 gob
+func parseHeader(value []byte) (name string, version int, payload []byte, err error) {
+	if !bytes.HasPrefix(value, []byte(headerPrefix)) {
+		return CodecGob, 0, value, nil
+	}
+
+	end := bytes.IndexByte(value, '\n')
+	if end < 0 {
+		return "", 0, nil, errMalformedHeader
+	}
+
+	name, versionStr, ok := strings.Cut(string(value[len(headerPrefix):end]), ":")
+	if !ok {
+		return "", 0, nil, errMalformedHeader
+	}
+	version, err = strconv.Atoi(versionStr)
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("malformed session version: %s", versionStr)
+	}
+
+	return name, version, value[end+1:], nil
+}
+
+// gobCodec : Go 에서만 읽을 수 있음
+type gobCodec struct{}
+
+func (gobCodec) Name() string { return CodecGob }
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// jsonCodec : redis-cli 로 확인하거나 다른 언어에서 읽을 때
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return CodecJSON }
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// msgpackCodec : 필드 이름은 json tag 를 따름
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string { return CodecMsgpack }
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
@@ -0,0 +1,109 @@
+package session
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+	"time"
+
+	"fiber-boilerplate/internal/models"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+func testData() *DataBlock {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &DataBlock{
+		Appuser: &models.AppuserBlock{
+			ID:         null.IntFrom(7),
+			UUID:       null.StringFrom("0f8fad5b-d9cb-469f-a165-70867728950e"),
+			ModifiedAt: null.TimeFrom(now),
+			Name:       null.StringFrom("kim"),
+			Withdraw:   null.BoolFrom(false),
+		},
+		Info: InfoBlock{ID: "sid-1", Kind: KindSID, AppuserUUID: "0f8fad5b-d9cb-469f-a165-70867728950e", Device: "curl/8", CreatedAt: now, LastSeenAt: now},
+	}
+}
+
+// sameData : codec 마다 time 의 location 표현이 달라 Equal 로 비교
+func sameData(t *testing.T, got *DataBlock, want *DataBlock) {
+	t.Helper()
+
+	if got.Appuser == nil {
+		t.Fatal("Appuser = nil")
+	}
+	if got.Appuser.ID != want.Appuser.ID || got.Appuser.UUID != want.Appuser.UUID || got.Appuser.Name != want.Appuser.Name ||
+		got.Appuser.Withdraw != want.Appuser.Withdraw || got.Appuser.Birthday.Valid != want.Appuser.Birthday.Valid {
+		t.Errorf("Appuser = %+v, want %+v", got.Appuser, want.Appuser)
+	}
+	if !got.Appuser.ModifiedAt.Time.Equal(want.Appuser.ModifiedAt.Time) {
+		t.Errorf("ModifiedAt = %s, want %s", got.Appuser.ModifiedAt.Time, want.Appuser.ModifiedAt.Time)
+	}
+	if got.Info.ID != want.Info.ID || got.Info.Kind != want.Info.Kind || got.Info.Device != want.Info.Device ||
+		!got.Info.CreatedAt.Equal(want.Info.CreatedAt) || !got.Info.LastSeenAt.Equal(want.Info.LastSeenAt) {
+		t.Errorf("Info = %+v, want %+v", got.Info, want.Info)
+	}
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	for _, name := range []string{CodecGob, CodecJSON, CodecMsgpack} {
+		t.Run(name, func(t *testing.T) {
+			codec, ok := CodecByName(name)
+			if !ok {
+				t.Fatalf("codec %s not registered", name)
+			}
+			value, err := encode(codec, testData())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "fbs:" + name + ":1\n"; !bytes.HasPrefix(value, []byte(want)) {
+				t.Errorf("header = %q, want %q", value[:bytes.IndexByte(value, '\n')+1], want)
+			}
+
+			data, err := decode(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sameData(t, data, testData())
+		})
+	}
+}
+
+// TestDecodeLegacy : 헤더 없이 gob 으로 저장하던 세션 (버전 0)
+func TestDecodeLegacy(t *testing.T) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(testData()); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := decode(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameData(t, data, testData())
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"no newline", "fbs:json:1"},
+		{"no version", "fbs:json\n{}"},
+		{"non-numeric version", "fbs:json:v1\n{}"},
+		{"unknown codec", "fbs:xml:1\n<data/>"},
+		{"unsupported version", "fbs:json:99\n{}"},
+		{"corrupt json", "fbs:json:1\n{\"appuser\":"},
+		{"corrupt msgpack", "fbs:msgpack:1\n\xc1"},
+		{"corrupt legacy gob", "not a gob stream"},
+		{"empty", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if data, err := decode([]byte(tt.value)); err == nil {
+				t.Errorf("decode = %+v, want error", data)
+			}
+		})
+	}
+}
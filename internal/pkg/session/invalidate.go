@@ -0,0 +1,48 @@
+package session
+
+import (
+	"context"
+
+	"fiber-boilerplate/internal/models"
+	logging "fiber-boilerplate/internal/pkg/logging"
+)
+
+// Follow : appuser 가 수정/삭제되어 commit 되면 그 사용자 세션의 캐시된 사용자 정보를 버림.
+// 세션은 모든 instance 가 같은 Redis 에 저장하므로 한 번 버리면 모든 instance 에 반영됨. Configure 후 한 번 호출
+func Follow(keyName string) {
+	store := GetStore(keyName)
+
+	models.OnAppuserChanged(func(ctx context.Context, appuserUUID string) {
+		if err := store.Evict(ctx, appuserUUID); err != nil {
+			logging.Warn(err, "session evict failed: %s", appuserUUID)
+		}
+	})
+}
+
+// Evict : 사용자의 모든 기기 세션에서 캐시된 사용자 정보만 버림.
+// 세션(기기 목록)은 유지되고 다음 요청에서 DB 로부터 다시 읽음
+func (s *StoreBlock) Evict(ctx context.Context, appuserUUID string) error {
+	ids, err := s.Index(ctx, appuserUUID)
+	if err != nil {
+		return err
+	}
+	// sid, jti 가 없는 토큰의 세션은 uuid 로 저장돼 있음
+	ids = append(ids, appuserUUID)
+
+	for _, id := range ids {
+		// 잠그지 않으면 middleware 의 touch 가 읽어 둔 사용자 정보를 다시 써서 버린 정보가 되살아날 수 있음
+		_, _, err := s.modify(ctx, id, func(data *DataBlock) bool {
+			if data.Appuser == nil {
+				return false
+			}
+			data.Appuser = nil
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+	logging.Trace("session evicted: %s", appuserUUID)
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"fiber-boilerplate/internal/models"
+	"fiber-boilerplate/internal/pkg/database"
+
+	"github.com/alicebob/miniredis/v2"
+	"gopkg.in/guregu/null.v4"
+)
+
+// testStore : miniredis 에 연결한 저장소
+func testStore(t *testing.T) *StoreBlock {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	database.Setup(json.RawMessage(`{"redis":{"conn":"` + mr.Addr() + `"}}`))
+
+	store := &StoreBlock{
+		KeyName: "test",
+		config:  defaultConfig,
+		codec:   jsonCodec{},
+		redis:   database.NewRedis(0, 60),
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	return store
+}
+
+func testSession(t *testing.T, store *StoreBlock, appuserUUID string, id string) {
+	t.Helper()
+
+	now := time.Now()
+	data := &DataBlock{
+		Appuser: &models.AppuserBlock{UUID: null.StringFrom(appuserUUID), Name: null.StringFrom("kim")},
+		Info:    InfoBlock{ID: id, Kind: KindSID, AppuserUUID: appuserUUID, CreatedAt: now, LastSeenAt: now},
+	}
+	ctx := context.Background()
+	if err := store.Set(ctx, id, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddIndex(ctx, appuserUUID, id); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestEvictThenTouch : Evict 전에 읽어 둔 세션으로 touch 해도 버린 사용자 정보가 되살아나지 않아야 함
+func TestEvictThenTouch(t *testing.T) {
+	store := testStore(t)
+	ctx := context.Background()
+	testSession(t, store, "appuser-1", "sid-1")
+
+	stale, _, err := store.Get(ctx, "sid-1")
+	if err != nil || stale.Appuser == nil {
+		t.Fatalf("Get = %+v, %v", stale, err)
+	}
+
+	if err := store.Evict(ctx, "appuser-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := time.Now().Add(time.Minute)
+	if _, found, err := store.modify(ctx, "sid-1", func(data *DataBlock) bool {
+		data.Info.LastSeenAt = seen
+		return true
+	}); err != nil || !found {
+		t.Fatalf("modify found = %v, err = %v", found, err)
+	}
+
+	data, found, err := store.Get(ctx, "sid-1")
+	if err != nil || !found {
+		t.Fatalf("Get found = %v, err = %v", found, err)
+	}
+	if data.Appuser != nil {
+		t.Errorf("Appuser = %+v, want nil after Evict", data.Appuser)
+	}
+	if !data.Info.LastSeenAt.Equal(seen) {
+		t.Errorf("LastSeenAt = %s, want %s", data.Info.LastSeenAt, seen)
+	}
+}
+
+// TestEvictConcurrentTouch : touch 와 동시에 Evict 해도 모든 세션에서 사용자 정보가 버려져야 함
+func TestEvictConcurrentTouch(t *testing.T) {
+	store := testStore(t)
+	ctx := context.Background()
+	ids := []string{"sid-1", "sid-2", "sid-3"}
+	for _, id := range ids {
+		testSession(t, store, "appuser-1", id)
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				_, _, err := store.modify(ctx, id, func(data *DataBlock) bool {
+					data.Info.LastSeenAt = time.Now()
+					return true
+				})
+				if err != nil {
+					t.Error(err)
+				}
+			}(id)
+		}
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := store.Evict(ctx, "appuser-1"); err != nil {
+			t.Error(err)
+		}
+	}()
+	wg.Wait()
+
+	for _, id := range ids {
+		data, found, err := store.Get(ctx, id)
+		if err != nil || !found {
+			t.Fatalf("Get(%s) found = %v, err = %v", id, found, err)
+		}
+		if data.Appuser != nil {
+			t.Errorf("Get(%s).Appuser = %+v, want nil", id, data.Appuser)
+		}
+	}
+}
+
+// TestModifyMissing : 없는 세션은 fn 을 부르지 않고 만들지도 않음
+func TestModifyMissing(t *testing.T) {
+	store := testStore(t)
+	ctx := context.Background()
+
+	called := false
+	_, found, err := store.modify(ctx, "missing", func(data *DataBlock) bool {
+		called = true
+		return true
+	})
+	if err != nil || found || called {
+		t.Fatalf("found = %v, called = %v, err = %v", found, called, err)
+	}
+	if _, found, _ := store.Get(ctx, "missing"); found {
+		t.Error("modify created a missing session")
+	}
+}
@@ -2,13 +2,18 @@ package session
 
 import (
 	"net/http"
+	"time"
 
 	logging "fiber-boilerplate/internal/pkg/logging"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// maxDeviceLen : User-Agent 는 길이 제한 후 저장
+const maxDeviceLen = 256
+
 // ManagerFunc :
 type ManagerFunc func(*fiber.Ctx, string) (int, *DataBlock, error)
 
@@ -57,13 +62,69 @@ func Middleware(keyName string, manager ManagerFunc, errorHandler ErrorHandlerFu
 				return errorHandler(ctx, http.StatusUnauthorized)
 			}
 
-			data, found, err := store.Get(ctx.Context(), uuid)
+			// 기기(로그인)별 세션
+			id, kind := ID(key)
+			now := time.Now()
+
+			data, found, err := store.Get(ctx.Context(), id)
 			if err != nil {
 				logging.Warn(err, "get key failed. url path: %s", ctx.Request().URI().Path())
 				return errorHandler(ctx, http.StatusInternalServerError, err)
 
+			} else if found && data.Appuser == nil {
+				// 사용자 정보가 바뀌어 버려진 세션 (Evict). 기기 정보는 유지하고 사용자 정보만 다시 로드.
+				// 로드부터 저장까지 잠가야 그 사이에 commit 된 변경의 Evict 를 덮어쓰지 않음
+				code := http.StatusOK
+				var loadErr error
+				reloaded, found, err := store.modify(ctx.Context(), id, func(data *DataBlock) bool {
+					if data.Appuser != nil {
+						// 다른 요청이 먼저 로드함
+						return false
+					}
+					var loaded *DataBlock
+					code, loaded, loadErr = manager(ctx, uuid)
+					if code != http.StatusOK {
+						return false
+					}
+					data.Appuser = loaded.Appuser
+					data.Info.LastSeenAt = now
+					data.Info.IP = utils.CopyString(ctx.IP())
+					return true
+				})
+				if code == http.StatusAccepted {
+					return next(ctx)
+				} else if code != http.StatusOK {
+					return errorHandler(ctx, code, loadErr)
+				}
+				if err != nil {
+					logging.Warn(err, "session reload failed: %s", id)
+					return errorHandler(ctx, http.StatusInternalServerError, err)
+				}
+				if !found {
+					// 잠그기 전에 삭제된 세션 (로그아웃, 만료)
+					return errorHandler(ctx, http.StatusUnauthorized)
+				}
+				ctx.Locals(ContextKeyData, reloaded)
+
 			} else if found {
 				// 저장돼 있는 세션이 있으면 통과
+				// 요청마다 쓰지 않도록 TouchInterval 이 지났을 때만 last seen 과 idle 만료 시간 갱신 (sliding)
+				if now.Sub(data.Info.LastSeenAt) >= store.config.TouchInterval {
+					ip := utils.CopyString(ctx.IP())
+					// 읽어 둔 data 를 그대로 쓰면 그 사이의 Evict 가 버린 사용자 정보가 되살아나므로 잠근 채 기기 정보만 갱신
+					_, _, err := store.modify(ctx.Context(), id, func(fresh *DataBlock) bool {
+						fresh.Info.LastSeenAt = now
+						fresh.Info.IP = ip
+						return true
+					})
+					if err != nil {
+						logging.Warn(err, "session touch failed: %s", id)
+					} else if err := store.AddIndex(ctx.Context(), uuid, id); err != nil {
+						logging.Warn(err, "session index touch failed: %s", uuid)
+					}
+					data.Info.LastSeenAt = now
+					data.Info.IP = ip
+				}
 				ctx.Locals(ContextKeyData, data)
 
 			} else {
@@ -78,7 +139,20 @@ func Middleware(keyName string, manager ManagerFunc, errorHandler ErrorHandlerFu
 					return errorHandler(ctx, code, err)
 				}
 
-				err = Create(ctx, uuid, data)
+				data.Info = InfoBlock{
+					ID:          id,
+					Kind:        kind,
+					AppuserUUID: uuid,
+					Device:      device(ctx),
+					IP:          utils.CopyString(ctx.IP()),
+					CreatedAt:   now,
+					LastSeenAt:  now,
+				}
+				if exp, err := key.GetExpirationTime(); err == nil && exp != nil {
+					data.Info.TokenExpiresAt = exp.Time
+				}
+
+				err = Create(ctx, id, data)
 				if err != nil {
 					logging.Trace("session created error. url path: %s", ctx.Request().URI().Path())
 					return errorHandler(ctx, http.StatusInternalServerError, err)
@@ -91,3 +165,12 @@ func Middleware(keyName string, manager ManagerFunc, errorHandler ErrorHandlerFu
 		}
 	}
 }
+
+// device : fiber 의 header 값은 요청이 끝나면 재사용되므로 복사
+func device(ctx *fiber.Ctx) string {
+	userAgent := utils.CopyString(ctx.Get(fiber.HeaderUserAgent))
+	if len(userAgent) > maxDeviceLen {
+		userAgent = userAgent[:maxDeviceLen]
+	}
+	return userAgent
+}
@@ -1,6 +1,10 @@
 package session
 
 import (
+	"context"
+	"sort"
+	"time"
+
 	"fiber-boilerplate/internal/defs"
 	"fiber-boilerplate/internal/models"
 	logging "fiber-boilerplate/internal/pkg/logging"
@@ -14,9 +18,42 @@ const (
 	ContextKeyData  = "fiber-boilerplate/#/sessionData"
 )
 
-// DataBlock :
+// 세션 ID 를 얻은 클레임
+const (
+	KindSID     = "sid"     // login 한 번 (refresh 로 access token 이 바뀌어도 유지)
+	KindJTI     = "jti"     // access token 하나
+	KindAppuser = "appuser" // sid, jti 가 없는 토큰. 사용자의 모든 기기가 세션 하나를 공유
+)
+
+// InfoBlock : 기기별 세션 정보
+type InfoBlock struct {
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"`
+	AppuserUUID string    `json:"appuserUuid"`
+	Device      string    `json:"device,omitempty"` // User-Agent
+	IP          string    `json:"ip,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	LastSeenAt  time.Time `json:"lastSeenAt"`
+	// 세션을 만든 access token 의 exp
+	TokenExpiresAt time.Time `json:"tokenExpiresAt"`
+	// 세션 만료 시각. 다시 사용하면 연장 (MaxExpiresAt 을 넘지 않음)
+	ExpiresAt time.Time `json:"expiresAt"`
+	// 생성 후 최대 유지 시각. zero 면 제한 없음
+	MaxExpiresAt time.Time `json:"maxExpiresAt"`
+}
+
+// DataBlock : 저장 형식은 codec.go 참고. 필드를 바꾸면 SchemaVersion 도 올림
 type DataBlock struct {
-	Appuser *models.AppuserBlock
+	Appuser *models.AppuserBlock `json:"appuser"`
+	Info    InfoBlock            `json:"info"`
+}
+
+// Remaining : 세션이 만료되기까지 남은 시간 (지금부터 사용하지 않는 경우)
+func (d *DataBlock) Remaining() time.Duration {
+	if d == nil || d.Info.ExpiresAt.IsZero() {
+		return 0
+	}
+	return max(time.Until(d.Info.ExpiresAt), 0)
 }
 
 // New :
@@ -42,26 +79,28 @@ func New(args ...interface{}) *DataBlock {
 	return data
 }
 
-// Create :
-func Create(ctx *fiber.Ctx, key string, data *DataBlock) error {
-	store := ctx.Locals(ContextKeyStore).(*StoreBlock)
-
-	err := store.Set(ctx.Context(), key, data)
-	if err == nil {
-		logging.Trace("session created: %s %+v", key, data)
+// ID : 클레임의 세션 ID. sid, jti, uuid 순으로 사용
+func ID(claims jwt.MapClaims) (id string, kind string) {
+	if sid, _ := claims["sid"].(string); sid != "" {
+		return sid, KindSID
 	}
-
-	return err
+	if jti, _ := claims["jti"].(string); jti != "" {
+		return jti, KindJTI
+	}
+	uuid, _ := claims["uuid"].(string)
+	return uuid, KindAppuser
 }
 
-// Update :
-func Update(ctx *fiber.Ctx, data *DataBlock) error {
+// Create : 세션 저장 및 사용자 세션 목록에 추가
+func Create(ctx *fiber.Ctx, key string, data *DataBlock) error {
 	store := ctx.Locals(ContextKeyStore).(*StoreBlock)
-	key := ctx.Locals(store.KeyName).(jwt.MapClaims)["uuid"].(string)
 
 	err := store.Set(ctx.Context(), key, data)
+	if err == nil && data.Info.AppuserUUID != "" {
+		err = store.AddIndex(ctx.Context(), data.Info.AppuserUUID, key)
+	}
 	if err == nil {
-		logging.Trace("session updated: %s %+v", key, data)
+		logging.Trace("session created: %s %+v", key, data)
 	}
 
 	return err
@@ -75,20 +114,113 @@ func FromContext(ctx *fiber.Ctx) *DataBlock {
 	return ctx.Locals(ContextKeyData).(*DataBlock)
 }
 
-// Destroy :
+// Claims : 인증된 요청의 JWT 클레임. 인증을 건너뛴 요청이면 nil
+func Claims(ctx *fiber.Ctx) jwt.MapClaims {
+	store, ok := ctx.Locals(ContextKeyStore).(*StoreBlock)
+	if !ok {
+		return nil
+	}
+	claims, _ := ctx.Locals(store.KeyName).(jwt.MapClaims)
+	return claims
+}
+
+// Destroy : 현재 기기의 세션만 삭제
 func Destroy(ctx *fiber.Ctx) error {
 	store := ctx.Locals(ContextKeyStore).(*StoreBlock)
-	key := ctx.Locals(store.KeyName).(jwt.MapClaims)["uuid"].(string)
+	claims := ctx.Locals(store.KeyName).(jwt.MapClaims)
+	key, _ := ID(claims)
+	uuid, _ := claims["uuid"].(string)
 
-	err := store.Del(ctx.Context(), key)
+	err := remove(ctx.Context(), store, uuid, key)
 	if err == nil {
 		logging.Trace("session destroyed: %s", key)
 	}
 
-	return store.Del(ctx.Context(), key)
+	return err
 }
 
-// GetStore :
+// List : 사용자의 세션 목록 (최근 사용 순). 만료된 세션은 목록에서 정리
+func List(ctx *fiber.Ctx, appuserUUID string) ([]InfoBlock, error) {
+	store, ok := ctx.Locals(ContextKeyStore).(*StoreBlock)
+	if !ok {
+		return nil, defs.ErrInvalid
+	}
+
+	ids, err := store.Index(ctx.Context(), appuserUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []string
+	infos := make([]InfoBlock, 0, len(ids))
+	for _, id := range ids {
+		data, found, err := store.Get(ctx.Context(), id)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			expired = append(expired, id)
+			continue
+		}
+		infos = append(infos, data.Info)
+	}
+	if err := store.RemoveIndex(ctx.Context(), appuserUUID, expired...); err != nil {
+		logging.Warn(err, "failed to prune session index: %s", appuserUUID)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].LastSeenAt.After(infos[j].LastSeenAt)
+	})
+
+	return infos, nil
+}
+
+// Remove : 사용자의 세션 하나 삭제 (토큰 폐기는 호출하는 쪽에서)
+func Remove(ctx *fiber.Ctx, appuserUUID string, id string) error {
+	store, ok := ctx.Locals(ContextKeyStore).(*StoreBlock)
+	if !ok {
+		return defs.ErrInvalid
+	}
+
+	err := remove(ctx.Context(), store, appuserUUID, id)
+	if err == nil {
+		logging.Trace("session removed: %s %s", appuserUUID, id)
+	}
+
+	return err
+}
+
+// Invalidate : 사용자의 모든 기기 세션 삭제 (요청한 사용자가 아닌 다른 사용자의 세션도 가능)
+func Invalidate(ctx *fiber.Ctx, appuserUUID string) error {
+	store, ok := ctx.Locals(ContextKeyStore).(*StoreBlock)
+	if !ok {
+		return defs.ErrInvalid
+	}
+
+	ids, err := store.Index(ctx.Context(), appuserUUID)
+	if err != nil {
+		return err
+	}
+	// sid, jti 가 없는 토큰의 세션은 uuid 로 저장돼 있음
+	ids = append(ids, appuserUUID)
+	for _, id := range ids {
+		if err := remove(ctx.Context(), store, appuserUUID, id); err != nil {
+			return err
+		}
+	}
+	logging.Trace("session invalidated: %s", appuserUUID)
+
+	return nil
+}
+
+// GetStore : Middleware 와 같은 저장소
 func GetStore(keyName string) *StoreBlock {
 	return newStore(keyName)
 }
+
+func remove(ctx context.Context, store *StoreBlock, appuserUUID string, id string) error {
+	if err := store.Del(ctx, id); err != nil {
+		return err
+	}
+	return store.RemoveIndex(ctx, appuserUUID, id)
+}
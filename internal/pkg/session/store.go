@@ -1,23 +1,108 @@
 package session
 
 import (
-	"bytes"
 	"context"
-	"encoding/gob"
-	"errors"
+	"sync"
 	"time"
 
 	"fiber-boilerplate/internal/defs"
 	"fiber-boilerplate/internal/pkg/database"
 	logging "fiber-boilerplate/internal/pkg/logging"
+	"fiber-boilerplate/internal/pkg/util"
 )
 
+// lockTTL : modify 의 잠금 만료 시간. 사용자 정보를 DB 에서 다시 읽는 동안에도 잠그므로 여유 있게
+const lockTTL = 5 * time.Second
+
+// ConfigBlock : 저장소별 세션 만료 설정
+type ConfigBlock struct {
+	// IdleTimeout : 마지막 사용 후 이 시간이 지나면 만료 (sliding)
+	IdleTimeout time.Duration
+	// MaxLifetime : 사용 여부와 관계없이 생성 후 이 시간이 지나면 만료. 0 이면 제한 없음
+	MaxLifetime time.Duration
+	// TouchInterval : 사용할 때 만료 시간을 연장하는 최소 간격 (요청마다 저장소에 쓰지 않도록)
+	TouchInterval time.Duration
+	// Codec : 저장 형식 (CodecGob, CodecJSON, CodecMsgpack 또는 RegisterCodec 으로 등록한 이름).
+	// 바꿔도 저장돼 있던 세션은 저장할 때의 codec 으로 읽음
+	Codec string
+}
+
+// defaultConfig : Configure 하지 않은 저장소
+var defaultConfig = ConfigBlock{
+	IdleTimeout:   2 * time.Hour,
+	TouchInterval: time.Minute,
+	Codec:         CodecJSON,
+}
+
+var (
+	configs   = map[string]ConfigBlock{}
+	configsMu sync.Mutex
+)
+
+// Configure : keyName 저장소의 만료 설정. Middleware, GetStore 보다 먼저 호출
+func Configure(keyName string, config ConfigBlock) {
+	configsMu.Lock()
+	defer configsMu.Unlock()
+	configs[keyName] = config
+}
+
+func storeConfig(keyName string) ConfigBlock {
+	configsMu.Lock()
+	config, ok := configs[keyName]
+	configsMu.Unlock()
+	if !ok {
+		return defaultConfig
+	}
+
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = defaultConfig.IdleTimeout
+	}
+	if config.MaxLifetime < 0 {
+		config.MaxLifetime = 0
+	}
+	if config.TouchInterval < 0 || config.TouchInterval > config.IdleTimeout {
+		config.TouchInterval = min(defaultConfig.TouchInterval, config.IdleTimeout)
+	}
+	if _, ok := CodecByName(config.Codec); !ok {
+		if config.Codec != "" {
+			logging.Warn(nil, "unknown session codec: %s. using %s", config.Codec, defaultConfig.Codec)
+		}
+		config.Codec = defaultConfig.Codec
+	}
+
+	return config
+}
+
 // StoreBlock :
 type StoreBlock struct {
 	KeyName string
+	config  ConfigBlock
+	codec   Codec
 	redis   *database.Redis
 }
 
+// expiry : 마지막 사용 시각 + IdleTimeout, 생성 시각 + MaxLifetime 중 이른 시각
+func (s *StoreBlock) expiry(data *DataBlock, now time.Time) (expiresAt time.Time, maxExpiresAt time.Time) {
+	lastSeen := data.Info.LastSeenAt
+	if lastSeen.IsZero() {
+		lastSeen = now
+	}
+	expiresAt = lastSeen.Add(s.config.IdleTimeout)
+
+	if s.config.MaxLifetime > 0 {
+		created := data.Info.CreatedAt
+		if created.IsZero() {
+			created = now
+		}
+		maxExpiresAt = created.Add(s.config.MaxLifetime)
+		if maxExpiresAt.Before(expiresAt) {
+			expiresAt = maxExpiresAt
+		}
+	}
+
+	return
+}
+
 // CloseConnection : Renamed from Close to prevent automatic cleanup by Fiber
 func (s *StoreBlock) CloseConnection() error {
 	if s == nil || s.redis == nil {
@@ -46,15 +131,20 @@ func (s *StoreBlock) Set(ctx context.Context, key string, data *DataBlock) error
 		ctx = context.Background()
 	}
 
-	var buf bytes.Buffer
+	now := time.Now()
+	data.Info.ExpiresAt, data.Info.MaxExpiresAt = s.expiry(data, now)
+	ttl := data.Info.ExpiresAt.Sub(now)
+	if ttl <= 0 {
+		// MaxLifetime 이 지난 세션은 저장하지 않음
+		return s.redis.Del(ctx, key)
+	}
 
-	encoder := gob.NewEncoder(&buf)
-	err := encoder.Encode(data)
+	value, err := encode(s.codec, data)
 	if err != nil {
 		return err
 	}
 
-	return s.redis.Set(ctx, key, buf.Bytes())
+	return s.redis.SetTTL(ctx, key, value, ttl)
 }
 
 // Get :
@@ -71,127 +161,163 @@ func (s *StoreBlock) Get(ctx context.Context, key string) (*DataBlock, bool, err
 		return nil, false, err
 
 	} else if found {
-		var data DataBlock
-		var buf bytes.Buffer
-
-		buf.Write(value.([]byte))
-		decoder := gob.NewDecoder(&buf)
-		err = decoder.Decode(&data)
+		// 읽을 수 없는 codec, migration 이 없는 이전 버전은 버리고 다시 검증 (새 세션 생성)
+		raw, _ := value.([]byte)
+		data, err := decode(raw)
 		if err != nil {
-			logging.Warn(err, "Failed to decode session data for key: %s. Deleting corrupted session.", key)
-			_ = s.redis.Del(ctx, key) // Clean up the invalid session
+			logging.Warn(err, "Failed to decode session data for key: %s. Discarding session.", key)
+			_ = s.redis.Del(ctx, key)
+			return nil, false, nil
+		}
+
+		// 저장소 TTL 과 별개로 한 번 더 확인 (이전 설정으로 저장된 세션)
+		if !data.Info.ExpiresAt.IsZero() && !time.Now().Before(data.Info.ExpiresAt) {
+			_ = s.redis.Del(ctx, key)
 			return nil, false, nil
 		}
 
-		return &data, true, nil
+		return data, true, nil
 	}
 
 	return nil, false, nil
 }
 
-// Flush :
-func (s *StoreBlock) Flush(ctx context.Context) error {
+// Index : 사용자의 세션 ID 목록. 만료된 세션의 ID 가 남아 있을 수 있음
+func (s *StoreBlock) Index(ctx context.Context, appuserUUID string) ([]string, error) {
 	if s == nil || s.redis == nil {
-		return defs.ErrInvalid
+		return nil, defs.ErrInvalid
 	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return s.redis.Flush(ctx)
+	return s.redis.SetMembers(ctx, indexKey(appuserUUID))
 }
 
-// Close :
-func (s *StoreBlock) Close() error {
+// AddIndex :
+func (s *StoreBlock) AddIndex(ctx context.Context, appuserUUID string, id string) error {
 	if s == nil || s.redis == nil {
 		return defs.ErrInvalid
 	}
-	return s.redis.Close()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return s.redis.SetAdd(ctx, indexKey(appuserUUID), id)
 }
 
-// SubscribeChannel :
-func (s *StoreBlock) SubscribeChannel(ctx context.Context, channel string) error {
+// RemoveIndex :
+func (s *StoreBlock) RemoveIndex(ctx context.Context, appuserUUID string, ids ...string) error {
 	if s == nil || s.redis == nil {
 		return defs.ErrInvalid
 	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return s.redis.SetRemove(ctx, indexKey(appuserUUID), ids...)
+}
+
+// Lock : 저장소의 redis 로 얻는 분산 잠금. Get/Set 은 잠그지 않으므로 상호 배제가 필요할 때만 사용
+func (s *StoreBlock) Lock(ctx context.Context, name string, ttl time.Duration) (*database.LockBlock, error) {
+	if s == nil || s.redis == nil {
+		return nil, defs.ErrInvalid
+	}
+	return s.redis.Lock(ctx, name, ttl)
+}
 
+// modify : 세션 하나를 잠근 채 다시 읽고 fn 으로 바꿔 저장. Get 과 Set 사이에 다른 곳(Evict, middleware 의 touch)의
+// 쓰기가 끼어들어 덮어쓰지 않도록, 같은 세션을 읽고 고쳐 쓰는 곳은 모두 modify 를 사용.
+// 세션이 없으면 fn 을 부르지 않고 found 는 false. fn 이 false 를 반환하면 저장하지 않음
+func (s *StoreBlock) modify(ctx context.Context, id string, fn func(data *DataBlock) bool) (data *DataBlock, found bool, err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	ctxWTO, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
-
-	// Subscriber Goroutine
-	go func() {
-		sub := s.redis.SubscribeChannel(ctx, channel)
-		ch := sub.Channel()
-		defer sub.Close()
-
-		for {
-			select {
-			case <-ctxWTO.Done():
-				// Context canceled or timeout, exit goroutine
-				logging.Trace("Context canceled or timeout, exit goroutine")
-				return
-			case msg := <-ch:
-				if msg == nil {
-					// Channel closed
-					logging.Trace("Channel closed")
-					return
-				}
-				logging.Trace("Received message:", msg.Payload)
-				// 메시지 받으면 종료
-				if len(ch) == 0 {
-					logging.Trace("Received all messages, exit goroutine")
-					// context cancel
-					cancel()
-					return
-				}
-			}
+	lock, err := s.Lock(ctx, lockName(id), lockTTL)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err := lock.Unlock(context.WithoutCancel(ctx)); err != nil {
+			logging.Warn(err, "session unlock failed: %s", id)
 		}
 	}()
 
-	// Main Goroutine waits
-	select {
-	case <-ctxWTO.Done():
-		// Timeout or cancellation
-		if errors.Is(ctxWTO.Err(), context.DeadlineExceeded) {
-			logging.Trace("Context timeout")
-			return defs.ErrTimeout
-		}
-
-		logging.Trace("Context Done")
-		return nil
+	data, found, err = s.Get(ctx, id)
+	if err != nil || !found {
+		return nil, found, err
 	}
+	if !fn(data) {
+		return data, true, nil
+	}
+
+	return data, true, s.Set(ctx, id, data)
 }
 
-// PublishChannel :
-func (s *StoreBlock) PublishChannel(ctx context.Context, channel string, message string) error {
+// Flush :
+func (s *StoreBlock) Flush(ctx context.Context) error {
 	if s == nil || s.redis == nil {
 		return defs.ErrInvalid
 	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return s.redis.PublishChannel(ctx, channel, message)
+	return s.redis.Flush(ctx)
+}
+
+// Close :
+func (s *StoreBlock) Close() error {
+	if s == nil || s.redis == nil {
+		return defs.ErrInvalid
+	}
+	return s.redis.Close()
+}
+
+// lockName : 세션 하나의 modify 잠금
+func lockName(id string) string {
+	return util.String.Concat("session/", id)
+}
+
+// indexKey : 세션 key(세션 ID) 와 겹치지 않도록 prefix 사용
+func indexKey(appuserUUID string) string {
+	return util.String.Concat("appuser/", appuserUUID)
 }
 
+// stores : keyName 마다 저장소(redis 연결) 하나를 공유
+var (
+	stores   = map[string]*StoreBlock{}
+	storesMu sync.Mutex
+)
+
 func newStore(keyName string) *StoreBlock {
+	storesMu.Lock()
+	defer storesMu.Unlock()
+	if store, ok := stores[keyName]; ok {
+		return store
+	}
+
 	var db int
-	var ttlSec int
 
 	switch keyName {
 	case "fiber-boilerplate/#/appuserID", "fiber-boilerplate/#/keyStore":
 		db = 0
-		ttlSec = 7200 // 2시간
 	default:
 		panic(defs.ErrInvalid)
 	}
 
-	logging.Info("Creating new store for keyName: %s", keyName)
+	config := storeConfig(keyName)
+	logging.Info("Creating new store for keyName: %s (idle %s, max lifetime %s, codec %s)", keyName, config.IdleTimeout, config.MaxLifetime, config.Codec)
+	codec, _ := CodecByName(config.Codec)
 
-	return &StoreBlock{
+	// 세션은 SetTTL 로 저장하므로 기본 ttl 은 사용자 세션 목록(index)에만 적용
+	store := &StoreBlock{
 		KeyName: keyName,
-		redis:   database.NewRedis(db, ttlSec),
+		config:  config,
+		codec:   codec,
+		redis:   database.NewRedis(db, int(config.IdleTimeout/time.Second)),
 	}
+	stores[keyName] = store
+
+	return store
 }